@@ -3,12 +3,16 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 
 	"github.com/mritd/gitflow-toolkit/v3/consts"
+	"github.com/mritd/gitflow-toolkit/v3/internal/git"
+	"github.com/mritd/gitflow-toolkit/v3/internal/llm"
 	"github.com/mritd/gitflow-toolkit/v3/internal/ui/branch"
+	"github.com/mritd/gitflow-toolkit/v3/internal/ui/common"
 )
 
 func init() {
@@ -21,25 +25,49 @@ func init() {
 
 // createBranchCommand creates a branch command for a specific commit type.
 func createBranchCommand(commitType, description string) *cobra.Command {
-	return &cobra.Command{
+	var useAI bool
+
+	cmd := &cobra.Command{
 		Use:   fmt.Sprintf("%s <name>", commitType),
 		Short: fmt.Sprintf("Create a %s branch (%s)", commitType, description),
 		Long: fmt.Sprintf(`Create a new branch with the %s/ prefix.
 
 This will create a branch named %s/<name> and switch to it.
 
+With --ai, the argument is a task description: the configured LLM suggests
+the branch type and name, which you can adjust before the branch is created.
+
 Example:
   gitflow-toolkit %s my-feature
-  # Creates and switches to branch: %s/my-feature`, commitType, commitType, commitType, commitType),
-		Args: cobra.ExactArgs(1),
+  # Creates and switches to branch: %s/my-feature
+
+  gitflow-toolkit %s --ai "users can't reset password when email has plus sign"
+  # Suggests e.g. fix/password-reset-plus-email for confirmation`, commitType, commitType, commitType, commitType, commitType),
+		Args:        cobra.ExactArgs(1),
+		Annotations: jsonAnnotation,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if jsonOutput() {
+				return runBranchJSON(cmd, commitType, args[0], useAI)
+			}
+			if common.PlainMode() {
+				return runBranchPlain(cmd, commitType, args[0], useAI)
+			}
+			if useAI {
+				return runBranchModel(cmd, branch.NewAIModel(commitType, args[0], llm.NewClient()))
+			}
 			return runBranch(cmd, commitType, args[0])
 		},
 	}
+	cmd.Flags().BoolVar(&useAI, "ai", false, "Suggest the branch type and name from a task description")
+	return cmd
 }
 
 func runBranch(cmd *cobra.Command, commitType, name string) error {
-	model := branch.NewModel(commitType, name)
+	return runBranchModel(cmd, branch.NewModel(commitType, name))
+}
+
+// runBranchModel runs the branch TUI and reports its error, if any.
+func runBranchModel(cmd *cobra.Command, model branch.Model) error {
 	p := tea.NewProgram(model)
 
 	finalModel, err := p.Run()
@@ -61,3 +89,59 @@ func runBranch(cmd *cobra.Command, commitType, name string) error {
 
 	return nil
 }
+
+// runBranchJSON creates the branch without TUI and prints the result as JSON.
+// With useAI the suggested branch is created without confirmation.
+func runBranchJSON(cmd *cobra.Command, commitType, arg string, useAI bool) error {
+	name := arg
+	if useAI {
+		var err error
+		commitType, name, err = branch.SuggestBranch(llm.NewClient(), commitType, arg)
+		if err != nil {
+			return printJSONError(cmd, jsonResult{}, err)
+		}
+	}
+
+	fullName := commitType + "/" + name
+	out, err := git.CreateTypedBranch(commitType, name)
+	if err != nil {
+		return printJSONError(cmd, jsonResult{Branch: fullName}, err)
+	}
+	printJSON(jsonResult{Command: cmd.Name(), Status: statusSuccess, Branch: fullName, Output: out})
+	return nil
+}
+
+// runBranchPlain creates the branch without TUI, confirming an AI suggestion with a y/n prompt.
+func runBranchPlain(cmd *cobra.Command, commitType, arg string, useAI bool) error {
+	name := arg
+	if useAI {
+		fmt.Println("Suggesting branch name...")
+		var err error
+		commitType, name, err = branch.SuggestBranch(llm.NewClient(), commitType, arg)
+		if err != nil {
+			return renderError(cmd, "Branch creation failed", err)
+		}
+
+		p := common.NewPrompter(os.Stdin, os.Stdout)
+		ok, err := p.Confirm(fmt.Sprintf("Create branch %s/%s?", commitType, name), true)
+		if err != nil && !errors.Is(err, common.ErrPromptClosed) {
+			return renderError(cmd, "Branch creation failed", err)
+		}
+		if !ok {
+			fmt.Print(common.RenderResult(common.Warning("Branch creation cancelled", "Operation was cancelled by user.")))
+			return nil
+		}
+	}
+
+	fullName := commitType + "/" + name
+	fmt.Printf("Creating branch %s...\n", fullName)
+	out, err := git.CreateTypedBranch(commitType, name)
+	if err != nil {
+		return renderError(cmd, "Branch creation failed", err)
+	}
+	if out == "" {
+		out = fmt.Sprintf("Branch %s created and checked out.", fullName)
+	}
+	fmt.Print(common.RenderResult(common.Success("Branch created", out)))
+	return nil
+}
@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/mritd/gitflow-toolkit/v3/config"
 	"github.com/mritd/gitflow-toolkit/v3/consts"
 	"github.com/mritd/gitflow-toolkit/v3/internal/git"
 	"github.com/mritd/gitflow-toolkit/v3/internal/ui/commit"
@@ -22,6 +25,9 @@ This command provides an interactive TUI to help you create
 properly formatted commit messages with type, scope, subject,
 body, and footer.
 
+When nothing is staged or tracked files have unstaged changes, a staging
+step lets you stage files or single hunks first (disable with gitflow.stage-browser = false).
+
 The commit message format follows the Angular specification:
   type(scope): subject
 
@@ -29,39 +35,113 @@ The commit message format follows the Angular specification:
 
   footer
 
-  Signed-off-by: Name <email>`,
-	RunE: runCommit,
+  Signed-off-by: Name <email>
+
+With --type, --scope and --subject the commit is created directly without the
+interactive flow, which is also how it runs in JSON output mode:
+  gitflow-toolkit ci --type feat --scope api --subject "add user endpoint"`,
+	Annotations: jsonAnnotation,
+	RunE:        runCommit,
 }
 
+var (
+	commitNoCache bool
+	commitReview  bool
+	commitFields  commit.Fields
+)
+
 func init() {
+	commitCmd.Flags().StringVar(&commitFields.Type, "type", "", "Commit type, commits without the interactive flow")
+	commitCmd.Flags().StringVar(&commitFields.Scope, "scope", "", "Commit scope")
+	commitCmd.Flags().StringVar(&commitFields.Subject, "subject", "", "Commit subject")
+	commitCmd.Flags().StringVar(&commitFields.Body, "body", "", "Commit body (default: the subject)")
+	commitCmd.Flags().StringVar(&commitFields.Footer, "footer", "", "Commit footer")
+	commitCmd.Flags().BoolVar(&commitNoCache, "no-cache", false, "Do not use cached AI file summaries")
+	commitCmd.Flags().BoolVar(&commitReview, "review", false, "Review staged changes with AI before generating the message")
 	rootCmd.AddCommand(commitCmd)
 }
 
 func runCommit(cmd *cobra.Command, _ []string) error {
+	// Commit directly from the flags when there is no TUI or fields were given
+	headless := jsonOutput() || commitFields != (commit.Fields{})
+
+	// Offer to stage changes first when nothing or only part of the tracked changes is staged
+	if !headless && !common.PlainMode() && config.GetBool(config.GitConfigStageBrowser, true) {
+		files, err := git.WorkingTreeStatus()
+		if err != nil {
+			return renderError(cmd, "Stage changes", err)
+		}
+		if commit.NeedsStaging(files) {
+			result := commit.RunStaging(files)
+			if result.Err != nil {
+				return renderError(cmd, "Stage changes", result.Err)
+			}
+			if result.Cancelled {
+				r := common.Warning("Commit cancelled", "Operation was cancelled by user.")
+				fmt.Print(common.RenderResult(r))
+				return nil
+			}
+		}
+	}
+
 	// Check if there are staged files
 	if err := git.HasStagedFiles(); err != nil {
 		return renderError(cmd, "No staged files", err)
 	}
 
 	// Check lucky commit configuration at startup
-	var luckyPrefix string
+	var luckyPrefix commit.LuckyPrefixFunc
 	if rawPrefix := git.GetLuckyPrefix(); rawPrefix != "" {
-		// Validate prefix format
-		prefix, err := git.ValidateLuckyPrefix(rawPrefix)
+		// Validate the prefix template; placeholders are expanded per commit
+		tmpl, err := git.ParseLuckyTemplate(rawPrefix)
 		if err != nil {
 			return renderError(cmd, "Lucky commit", err)
 		}
-
-		// Check lucky_commit executable exists
-		if err := git.CheckLuckyCommit(); err != nil {
+		// Sample the counter of the upcoming commit; an unborn branch counts 0
+		counter, _ := git.CommitCount()
+		if err := tmpl.Validate(time.Now(), counter+1); err != nil {
 			return renderError(cmd, "Lucky commit", err)
 		}
 
-		luckyPrefix = prefix
+		// The external engine needs the lucky_commit executable
+		if git.GetLuckyEngine() == consts.LuckyEngineExternal {
+			if err := git.CheckLuckyCommit(); err != nil {
+				return renderError(cmd, "Lucky commit", err)
+			}
+		}
+
+		// Expand at commit time, after the commit exists so {counter} includes it
+		luckyPrefix = func(msg git.CommitMessage) (string, error) {
+			return tmpl.Expand(git.LuckyContext{
+				Type:    msg.Type,
+				Time:    time.Now(),
+				Counter: git.CommitCount,
+			})
+		}
 	}
 
-	// Run the interactive commit flow (pass luckyPrefix)
-	result := commit.Run(luckyPrefix)
+	if headless && commitFields.Type == "" {
+		return renderError(cmd, "Commit failed", errors.New("commit type is required without the interactive flow, set it with --type"))
+	}
+	if headless && commitReview {
+		return renderError(cmd, "Commit failed", errors.New("--review needs the interactive flow"))
+	}
+
+	if common.PlainMode() && commitReview {
+		return renderError(cmd, "Commit failed", errors.New("--review is not available in the plain UI"))
+	}
+
+	var result commit.Result
+	if headless {
+		result = commit.RunHeadless(commitFields, luckyPrefix)
+	} else {
+		// Run the interactive commit flow (pass luckyPrefix)
+		result = commit.Run(luckyPrefix, commit.Options{NoCache: commitNoCache, Review: commitReview, Plain: common.PlainMode()})
+	}
+
+	if jsonOutput() {
+		return printCommitJSON(cmd, result)
+	}
 
 	if result.Cancelled {
 		r := common.Warning("Commit cancelled", "Operation was cancelled by user.")
@@ -92,16 +172,9 @@ func runCommit(cmd *cobra.Command, _ []string) error {
 	}
 
 	// Handle lucky commit results
-	if result.LuckySkipped {
-		r := common.Warning("Commit created (lucky skipped)", content)
-		r.Note = "lucky commit skipped, original commit preserved"
-		fmt.Print(common.RenderResult(r))
-		return nil
-	}
-
-	if result.LuckyFailed != nil {
-		r := common.Warning("Commit created (lucky failed)", content)
-		r.Note = fmt.Sprintf("lucky commit failed: %s, original commit preserved", result.LuckyFailed)
+	if title, note := luckyOutcome(result); note != "" {
+		r := common.Warning(title, content)
+		r.Note = note
 		fmt.Print(common.RenderResult(r))
 		return nil
 	}
@@ -111,3 +184,43 @@ func runCommit(cmd *cobra.Command, _ []string) error {
 	fmt.Print(common.RenderResult(r))
 	return nil
 }
+
+// luckyOutcome returns the result title and note when the lucky commit did not
+// complete, or an empty note if it succeeded or was not enabled.
+func luckyOutcome(result commit.Result) (title, note string) {
+	switch {
+	case result.LuckySkipped:
+		return "Commit created (lucky skipped)", "lucky commit skipped, original commit preserved"
+	case result.LuckyTimedOut > 0:
+		return "Commit created (lucky timed out)",
+			fmt.Sprintf("no lucky hash found within %s, original commit preserved", result.LuckyTimedOut)
+	case result.LuckyFailed != nil:
+		return "Commit created (lucky failed)",
+			fmt.Sprintf("lucky commit failed: %s, original commit preserved", result.LuckyFailed)
+	}
+	return "", ""
+}
+
+// printCommitJSON prints the commit result as JSON.
+func printCommitJSON(cmd *cobra.Command, result commit.Result) error {
+	branch, _ := git.CurrentBranch()
+	out := jsonResult{Branch: branch}
+	if result.Err != nil {
+		// The message is only set once the fields were valid
+		if result.Message.Type != "" {
+			out.Message = newJSONMessage(result.Message)
+		}
+		return printJSONError(cmd, out, result.Err)
+	}
+
+	out.Command = cmd.Name()
+	out.Status = statusSuccess
+	out.Hash = result.Hash
+	out.Message = newJSONMessage(result.Message)
+	if _, note := luckyOutcome(result); note != "" {
+		out.Status = statusWarning
+		out.Warnings = []string{note}
+	}
+	printJSON(out)
+	return nil
+}
@@ -2,11 +2,9 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
-	"golang.org/x/term"
 
 	"github.com/mritd/gitflow-toolkit/v3/consts"
 	"github.com/mritd/gitflow-toolkit/v3/internal/ui/common"
@@ -31,7 +29,8 @@ After installation, you can use commands like:
   git feat    - Create feature branch
   git fix     - Create fix branch
   ...`,
-	RunE: runInstall,
+	Annotations: jsonAnnotation,
+	RunE:        runInstall,
 }
 
 // uninstallCmd represents the uninstall command.
@@ -43,7 +42,8 @@ var uninstallCmd = &cobra.Command{
 This will:
   1. Remove all git command symlinks
   2. Remove the binary`,
-	RunE: runUninstall,
+	Annotations: jsonAnnotation,
+	RunE:        runUninstall,
 }
 
 func init() {
@@ -54,11 +54,6 @@ func init() {
 	rootCmd.AddCommand(uninstallCmd)
 }
 
-// isInteractive checks if we're running in an interactive terminal.
-func isInteractive() bool {
-	return term.IsTerminal(int(os.Stdout.Fd()))
-}
-
 func runInstall(cmd *cobra.Command, _ []string) error {
 	// Check if we have write permission to the install directory
 	if install.NeedsSudo(installDir) {
@@ -73,8 +68,12 @@ func runInstall(cmd *cobra.Command, _ []string) error {
 
 	tasks := install.InstallTasks(paths)
 
-	// If not interactive (e.g., in Docker/CI), run tasks directly
-	if !isInteractive() {
+	if jsonOutput() {
+		return runTasksJSON(cmd, tasks)
+	}
+
+	// Without a capable terminal (e.g., in Docker/CI), run tasks directly
+	if common.PlainMode() {
 		return runTasksNonInteractive(cmd, "Installing gitflow-toolkit", tasks)
 	}
 
@@ -112,8 +111,12 @@ func runUninstall(cmd *cobra.Command, _ []string) error {
 
 	tasks := install.UninstallTasks(paths)
 
-	// If not interactive, run tasks directly
-	if !isInteractive() {
+	if jsonOutput() {
+		return runTasksJSON(cmd, tasks)
+	}
+
+	// Without a capable terminal, run tasks directly
+	if common.PlainMode() {
 		return runTasksNonInteractive(cmd, "Uninstalling gitflow-toolkit", tasks)
 	}
 
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/mritd/gitflow-toolkit/v3/config"
+	"github.com/mritd/gitflow-toolkit/v3/consts"
+	"github.com/mritd/gitflow-toolkit/v3/internal/git"
+	"github.com/mritd/gitflow-toolkit/v3/internal/ui/common"
+)
+
+// outputFormat is the --output flag value, empty to detect it from stdout.
+var outputFormat string
+
+// jsonAnnotation marks the commands that print a JSON result in JSON output mode.
+// Errors of other commands are always rendered as text.
+var jsonAnnotation = map[string]string{"output": "json"}
+
+// Result status values in JSON output.
+const (
+	statusSuccess = "success"
+	statusWarning = "warning"
+	statusError   = "error"
+)
+
+// jsonResult is the single object a command prints in JSON output mode.
+type jsonResult struct {
+	Command  string       `json:"command"`
+	Status   string       `json:"status"`
+	Hash     string       `json:"hash,omitempty"`
+	Branch   string       `json:"branch,omitempty"`
+	Message  *jsonMessage `json:"message,omitempty"`
+	Output   string       `json:"output,omitempty"`
+	Tasks    []jsonTask   `json:"tasks,omitempty"`
+	Warnings []string     `json:"warnings,omitempty"`
+	Error    string       `json:"error,omitempty"`
+}
+
+// jsonMessage holds the commit message fields.
+type jsonMessage struct {
+	Type    string `json:"type"`
+	Scope   string `json:"scope"`
+	Subject string `json:"subject"`
+	Body    string `json:"body,omitempty"`
+	Footer  string `json:"footer,omitempty"`
+	SOB     string `json:"sob,omitempty"`
+}
+
+// jsonTask is the outcome of an install or uninstall task.
+type jsonTask struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", "",
+		"Output format: text or json (default json when stdout is not a terminal)")
+}
+
+// validateOutputFormat checks the --output flag value.
+func validateOutputFormat() error {
+	switch outputFormat {
+	case "", consts.OutputText, consts.OutputJSON:
+		return nil
+	}
+	return fmt.Errorf("invalid output format %q, use %s or %s", outputFormat, consts.OutputText, consts.OutputJSON)
+}
+
+// isInteractive checks if we're running in an interactive terminal.
+func isInteractive() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// plainUI reports whether line-oriented prompts replace the TUI: set with
+// gitflow.ui=plain, or automatically when the terminal cannot run the TUI.
+func plainUI() bool {
+	switch config.GetString(config.GitConfigUI, consts.UIAuto) {
+	case consts.UIPlain:
+		return true
+	case consts.UITUI:
+		return false
+	}
+	return os.Getenv("TERM") == "dumb" || !isInteractive() || !term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// jsonOutput reports whether results are printed as JSON instead of the TUI.
+func jsonOutput() bool {
+	if outputFormat == "" {
+		return !isInteractive()
+	}
+	return outputFormat == consts.OutputJSON
+}
+
+// jsonErrors reports whether errors of cmd are printed as a JSON object.
+func jsonErrors(cmd *cobra.Command) bool {
+	return jsonOutput() && cmd.Annotations["output"] == "json"
+}
+
+// printJSON prints v as one line of JSON on stdout.
+func printJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(v)
+}
+
+// printJSONError prints a failed result and silences cobra's default output.
+func printJSONError(cmd *cobra.Command, out jsonResult, err error) error {
+	out.Command = cmd.Name()
+	out.Status = statusError
+	out.Error = err.Error()
+	printJSON(out)
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	return err
+}
+
+// newJSONMessage converts a commit message for JSON output.
+func newJSONMessage(msg git.CommitMessage) *jsonMessage {
+	return &jsonMessage{
+		Type:    msg.Type,
+		Scope:   msg.Scope,
+		Subject: msg.Subject,
+		Body:    msg.Body,
+		Footer:  msg.Footer,
+		SOB:     msg.SOB,
+	}
+}
+
+// runTasksJSON runs tasks without TUI and prints their outcomes as JSON.
+func runTasksJSON(cmd *cobra.Command, tasks []common.Task) error {
+	out := jsonResult{Command: cmd.Name(), Status: statusSuccess}
+	failed := false
+	for _, task := range tasks {
+		t := jsonTask{Name: task.Name, Status: statusSuccess}
+		if err := task.Run(); err != nil {
+			t.Error = err.Error()
+			if common.IsWarnErr(err) {
+				t.Status = statusWarning
+				out.Warnings = append(out.Warnings, task.Name+": "+err.Error())
+			} else {
+				t.Status = statusError
+				failed = true
+			}
+		}
+		out.Tasks = append(out.Tasks, t)
+	}
+
+	if failed {
+		return printJSONError(cmd, out, fmt.Errorf("some tasks failed"))
+	}
+	if len(out.Warnings) > 0 {
+		out.Status = statusWarning
+	}
+	printJSON(out)
+	return nil
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/atotto/clipboard"
+	"github.com/spf13/cobra"
+
+	"github.com/mritd/gitflow-toolkit/v3/consts"
+	"github.com/mritd/gitflow-toolkit/v3/internal/ui/commit"
+	"github.com/mritd/gitflow-toolkit/v3/internal/ui/common"
+)
+
+// prDescCmd represents the pr-desc command.
+var prDescCmd = &cobra.Command{
+	Use:   consts.CmdPRDesc,
+	Short: "Generate a PR title and description with AI",
+	Long: `Generate a pull request title and Markdown description for the current branch.
+
+The commits and the combined diff since the branch diverged from the main
+branch are summarized with the configured LLM. The result contains a title
+and a body with Summary, Changes (grouped by commit type) and Testing sections.
+
+The description is copied to the clipboard, or written to a file with --file.`,
+	RunE: runPRDesc,
+}
+
+var (
+	prDescFile    string
+	prDescNoCache bool
+)
+
+func init() {
+	prDescCmd.Flags().StringVarP(&prDescFile, "file", "f", "", "Write the description to a file instead of the clipboard")
+	prDescCmd.Flags().BoolVar(&prDescNoCache, "no-cache", false, "Do not use cached AI file summaries")
+	rootCmd.AddCommand(prDescCmd)
+}
+
+func runPRDesc(cmd *cobra.Command, _ []string) error {
+	result := commit.RunPRDescription(commit.Options{NoCache: prDescNoCache})
+
+	if result.Cancelled {
+		r := common.Warning("PR description cancelled", "Operation was cancelled by user.")
+		fmt.Print(common.RenderResult(r))
+		return nil
+	}
+
+	if result.Err != nil {
+		return renderError(cmd, "PR description failed", result.Err)
+	}
+
+	desc := result.Description
+	content := common.StyleCommitType.Render(desc.Title) + "\n\n" + desc.Body
+
+	if prDescFile != "" {
+		if err := os.WriteFile(prDescFile, []byte(desc.String()), 0o644); err != nil {
+			return renderError(cmd, "PR description failed", fmt.Errorf("failed to write %s: %w", prDescFile, err))
+		}
+		r := common.Success("PR description generated", content)
+		r.Note = fmt.Sprintf("written to %s (base: %s)", prDescFile, desc.Base)
+		fmt.Print(common.RenderResult(r))
+		return nil
+	}
+
+	if err := clipboard.WriteAll(desc.String()); err != nil {
+		r := common.Warning("PR description generated", content)
+		r.Note = fmt.Sprintf("clipboard unavailable: %s, use --file to write a file", err)
+		fmt.Print(common.RenderResult(r))
+		return nil
+	}
+
+	r := common.Success("PR description generated", content)
+	r.Note = fmt.Sprintf("copied to clipboard (base: %s)", desc.Base)
+	fmt.Print(common.RenderResult(r))
+	return nil
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/mritd/gitflow-toolkit/v3/consts"
+	"github.com/mritd/gitflow-toolkit/v3/internal/git"
+	"github.com/mritd/gitflow-toolkit/v3/internal/ui/common"
 	"github.com/mritd/gitflow-toolkit/v3/internal/ui/push"
 )
 
@@ -20,7 +22,8 @@ var pushCmd = &cobra.Command{
 
 This is equivalent to:
   git push origin <current-branch>`,
-	RunE: runPush,
+	Annotations: jsonAnnotation,
+	RunE:        runPush,
 }
 
 func init() {
@@ -28,6 +31,13 @@ func init() {
 }
 
 func runPush(cmd *cobra.Command, _ []string) error {
+	if jsonOutput() {
+		return runPushJSON(cmd)
+	}
+	if common.PlainMode() {
+		return runPushPlain(cmd)
+	}
+
 	model := push.NewModel()
 	p := tea.NewProgram(model)
 
@@ -50,3 +60,25 @@ func runPush(cmd *cobra.Command, _ []string) error {
 
 	return nil
 }
+
+// runPushJSON pushes without TUI and prints the result as JSON.
+func runPushJSON(cmd *cobra.Command) error {
+	branch, _ := git.CurrentBranch()
+	out, err := git.Push()
+	if err != nil {
+		return printJSONError(cmd, jsonResult{Branch: branch}, err)
+	}
+	printJSON(jsonResult{Command: cmd.Name(), Status: statusSuccess, Branch: branch, Output: out})
+	return nil
+}
+
+// runPushPlain pushes without TUI and prints the result as plain text.
+func runPushPlain(cmd *cobra.Command) error {
+	fmt.Println("Pushing to origin...")
+	out, err := git.Push()
+	if err != nil {
+		return renderError(cmd, "Push failed", err)
+	}
+	fmt.Print(common.RenderResult(common.Success("Push completed", out)))
+	return nil
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mritd/gitflow-toolkit/v3/consts"
+	"github.com/mritd/gitflow-toolkit/v3/internal/git"
+	"github.com/mritd/gitflow-toolkit/v3/internal/ui/commit"
+	"github.com/mritd/gitflow-toolkit/v3/internal/ui/common"
+)
+
+// reviewCmd represents the review command.
+var reviewCmd = &cobra.Command{
+	Use:   consts.CmdReview,
+	Short: "Review staged changes with AI",
+	Long: `Review the staged changes with the configured LLM before committing.
+
+Each staged file is reviewed in parallel for bugs, security problems and
+leftover debug code. Findings are shown grouped by file with a severity
+of high, medium or low.
+
+To review as part of the AI commit flow, use "git ci --review" or set
+gitflow.llm-review to true.`,
+	RunE: runReview,
+}
+
+var reviewNoCache bool
+
+func init() {
+	reviewCmd.Flags().BoolVar(&reviewNoCache, "no-cache", false, "Do not use cached AI review results")
+	rootCmd.AddCommand(reviewCmd)
+}
+
+func runReview(cmd *cobra.Command, _ []string) error {
+	if err := git.HasStagedFiles(); err != nil {
+		return renderError(cmd, "No staged files", err)
+	}
+
+	result := commit.RunReview(commit.Options{NoCache: reviewNoCache})
+
+	if result.Cancelled {
+		r := common.Warning("Review cancelled", "Operation was cancelled by user.")
+		fmt.Print(common.RenderResult(r))
+		return nil
+	}
+
+	if result.Err != nil {
+		return renderError(cmd, "Review failed", result.Err)
+	}
+
+	if len(result.Files) == 0 {
+		r := common.Success("Review passed", fmt.Sprintf("No findings in %d reviewed file(s).", result.Reviewed))
+		fmt.Print(common.RenderResult(r))
+		return nil
+	}
+
+	var count int
+	for _, f := range result.Files {
+		count += len(f.Findings)
+	}
+	r := common.Warning("Review findings", commit.FormatReview(result.Files))
+	r.Note = fmt.Sprintf("%d finding(s) in %d of %d reviewed file(s)", count, len(result.Files), result.Reviewed)
+	fmt.Print(common.RenderResult(r))
+	return nil
+}
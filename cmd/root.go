@@ -55,13 +55,21 @@ Available commit types:
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	if c, err := rootCmd.ExecuteC(); err != nil {
+		// Errors cobra reports itself (bad flags or arguments) still need a JSON object
+		if jsonErrors(c) && !c.SilenceErrors {
+			printJSON(jsonResult{Command: c.Name(), Status: statusError, Error: err.Error()})
+		}
 		os.Exit(1)
 	}
 }
 
 // renderError renders an error using the Result component and silences cobra's default output.
+// In JSON output mode it prints an error object instead, for commands with JSON support.
 func renderError(cmd *cobra.Command, title string, err error) error {
+	if jsonErrors(cmd) {
+		return printJSONError(cmd, jsonResult{}, err)
+	}
 	r := common.Error(title, err.Error())
 	fmt.Print(common.RenderResult(r))
 	cmd.SilenceUsage = true
@@ -72,6 +80,25 @@ func renderError(cmd *cobra.Command, title string, err error) error {
 func init() {
 	// Disable completion command
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.PersistentPreRunE = setup
+}
+
+// setup validates the global flags and applies the UI mode and theme before any command runs.
+func setup(cmd *cobra.Command, _ []string) error {
+	if err := validateOutputFormat(); err != nil {
+		return err
+	}
+
+	common.SetPlainMode(plainUI())
+
+	// A typo in the theme settings should not break every command: warn on
+	// stderr, keeping JSON output on stdout intact, and use the default theme
+	theme, err := common.LoadTheme()
+	common.ApplyTheme(theme)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, common.StyleWarning.Render(common.SymbolWarning+" Using the default theme: "+err.Error()))
+	}
+	return nil
 }
 
 // SetVersionInfo sets the version information.
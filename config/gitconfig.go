@@ -2,6 +2,7 @@ package config
 
 import (
 	"os/exec"
+	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
@@ -17,19 +18,41 @@ const (
 	GitConfigLLMAPIHost               = "llm-api-host"
 	GitConfigLLMAPIPath               = "llm-api-path"
 	GitConfigLLMModel                 = "llm-model"
+	GitConfigLLMAzureDeployment       = "llm-azure-deployment"
+	GitConfigLLMAzureAPIVersion       = "llm-azure-api-version"
 	GitConfigLLMTemperature           = "llm-temperature"
 	GitConfigLLMRequestTimeout        = "llm-request-timeout"
 	GitConfigLLMMaxRetries            = "llm-max-retries"
 	GitConfigLLMOutputLang            = "llm-output-lang"
 	GitConfigLLMDiffContext           = "llm-diff-context"
 	GitConfigLLMMaxConcurrency        = "llm-max-concurrency"
+	GitConfigLLMMaxFileTokens         = "llm-max-file-tokens"
+	GitConfigLLMSinglePassTokens      = "llm-single-pass-tokens"
+	GitConfigLLMHistoryContext        = "llm-history-context"
+	GitConfigLLMHistoryCommits        = "llm-history-commits"
+	GitConfigLLMStructuredOutput      = "llm-structured-output"
+	GitConfigLLMCandidates            = "llm-candidates"
+	GitConfigLLMCache                 = "llm-cache"
+	GitConfigLLMCacheTTL              = "llm-cache-ttl"
+	GitConfigLLMCacheMaxMB            = "llm-cache-max-mb"
+	GitConfigLLMReview                = "llm-review"
+	GitConfigLLMIgnore                = "llm-ignore"
+	GitConfigLLMRedact                = "llm-redact"
+	GitConfigLLMRedactPatterns        = "llm-redact-patterns"
+	GitConfigLLMRedactBlock           = "llm-redact-block"
 	GitConfigLLMFileAnalysisPrompt    = "llm-file-analysis-prompt"
-	GitConfigLLMCommitPromptEN        = "llm-commit-prompt-en"
-	GitConfigLLMCommitPromptZH        = "llm-commit-prompt-zh"
+	GitConfigLLMCommitPromptPrefix    = "llm-commit-prompt-" // followed by a language code, e.g. llm-commit-prompt-ja
 	GitConfigLLMCommitPromptBilingual = "llm-commit-prompt-bilingual"
+	GitConfigLLMExamplesDir           = "llm-examples-dir"
 	GitConfigLuckyCommitPrefix        = "lucky-commit-prefix"
+	GitConfigLuckyCommitTimeout       = "lucky-commit-timeout"
+	GitConfigLuckyCommitEngine        = "lucky-commit-engine"
 	GitConfigSSHStrictHostKey         = "ssh-strict-host-key"
 	GitConfigBranchAutoDetect         = "branch-auto-detect"
+	GitConfigStageBrowser             = "stage-browser"
+	GitConfigTheme                    = "theme"
+	GitConfigUI                       = "ui"
+	GitConfigColorPrefix              = "color-" // followed by a color name, e.g. color-primary
 )
 
 // gitConfig runs git config --get and returns the value.
@@ -48,6 +71,53 @@ func gitConfig(key string) string {
 	return strings.TrimSpace(string(out))
 }
 
+// gitConfigAll runs git config --get-all and returns all values of a multi-valued key.
+func gitConfigAll(key string) []string {
+	fullKey := GitConfigSection + "." + key
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("git.exe", "config", "--get-all", fullKey)
+	} else {
+		cmd = exec.Command("git", "config", "--get-all", fullKey)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	var values []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			values = append(values, line)
+		}
+	}
+	return values
+}
+
+// gitConfigPrefixed runs git config --get-regexp and returns the values of all keys
+// starting with prefix, keyed by the rest of the key.
+func gitConfigPrefixed(prefix string) map[string]string {
+	fullPrefix := GitConfigSection + "." + prefix
+	pattern := "^" + regexp.QuoteMeta(fullPrefix)
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("git.exe", "config", "--get-regexp", pattern)
+	} else {
+		cmd = exec.Command("git", "config", "--get-regexp", pattern)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	values := make(map[string]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		key, val, _ := strings.Cut(strings.TrimSpace(line), " ")
+		if name := strings.TrimPrefix(key, fullPrefix); name != key && name != "" {
+			values[name] = strings.TrimSpace(val)
+		}
+	}
+	return values
+}
+
 // GetString returns a config value from gitconfig, or default if not set.
 func GetString(gitKey, defaultVal string) string {
 	if val := gitConfig(gitKey); val != "" {
@@ -56,6 +126,18 @@ func GetString(gitKey, defaultVal string) string {
 	return defaultVal
 }
 
+// GetStrings returns all values of a multi-valued config key, or nil if not set.
+// Values are added with: git config --add gitflow.<key> <value>
+func GetStrings(gitKey string) []string {
+	return gitConfigAll(gitKey)
+}
+
+// GetPrefixed returns all config values whose key starts with prefix, keyed by the
+// rest of the key in lowercase (e.g. color-primary -> primary), or nil if none are set.
+func GetPrefixed(prefix string) map[string]string {
+	return gitConfigPrefixed(prefix)
+}
+
 // GetInt returns an int config value from gitconfig, or default if not set.
 func GetInt(gitKey string, defaultVal int) int {
 	if val := gitConfig(gitKey); val != "" {
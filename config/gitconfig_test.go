@@ -13,6 +13,22 @@ func TestGetString(t *testing.T) {
 	})
 }
 
+func TestGetStrings(t *testing.T) {
+	t.Run("returns nil when nothing set", func(t *testing.T) {
+		if got := GetStrings("nonexistent-key-12345"); got != nil {
+			t.Errorf("GetStrings() = %v, want nil", got)
+		}
+	})
+}
+
+func TestGetPrefixed(t *testing.T) {
+	t.Run("returns empty when nothing set", func(t *testing.T) {
+		if got := GetPrefixed("nonexistent-prefix-12345-"); len(got) != 0 {
+			t.Errorf("GetPrefixed() = %v, want empty", got)
+		}
+	})
+}
+
 func TestGetInt(t *testing.T) {
 	t.Run("returns default when nothing set", func(t *testing.T) {
 		got := GetInt("nonexistent-key-12345", 42)
@@ -20,6 +20,29 @@ const (
 const (
 	CmdCommit = "ci"
 	CmdPush   = "ps"
+	CmdPRDesc = "pr-desc"
+	CmdReview = "review"
+)
+
+// Output formats selected with --output.
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+// UI modes selected with gitflow.ui.
+const (
+	UIAuto  = "auto"  // plain when the terminal cannot run the TUI
+	UITUI   = "tui"   // always use the TUI
+	UIPlain = "plain" // line-oriented prompts for screen readers and dumb terminals
+)
+
+// Built-in UI themes selected with gitflow.theme.
+const (
+	ThemeDefault      = "default"
+	ThemeHighContrast = "high-contrast"
+	ThemeMonochrome   = "monochrome"
+	ThemeSolarized    = "solarized"
 )
 
 // CommitType represents a commit type with its name and description.
@@ -51,6 +74,16 @@ const (
 
 	// LuckyCommitURL is the download URL for lucky_commit.
 	LuckyCommitURL = "https://github.com/not-an-aardvark/lucky-commit"
+
+	// LuckyCommitDefaultTimeout is the default search timeout (0 means no timeout).
+	LuckyCommitDefaultTimeout = 0 * time.Second
+
+	// LuckyEngineBuiltin searches lucky hashes in-process; LuckyEngineExternal runs lucky_commit.
+	LuckyEngineBuiltin  = "builtin"
+	LuckyEngineExternal = "external"
+
+	// LuckyCalibrationTime is how long the hash rate is measured before searching.
+	LuckyCalibrationTime = 200 * time.Millisecond
 )
 
 // LLM defaults.
@@ -61,6 +94,22 @@ const (
 	LLMDefaultLang           = "en"
 	LLMDefaultTemperature    = 0.3
 	LLMDefaultConcurrency    = 3
+	LLMDefaultMaxFileTokens  = 4000
+	LLMDefaultSinglePass     = 1500
+	LLMDefaultHistoryCommits = 5
+	LLMDefaultCandidates     = 1
+	LLMMaxCandidates         = 5
+	LLMDefaultCacheTTL       = 7 * 24 * time.Hour
+	LLMDefaultCacheMaxMB     = 50
+
+	// LLMCandidateTemperatureStep is added to the temperature of each additional candidate message.
+	LLMCandidateTemperatureStep = 0.2
+
+	// LLMStructuredRepairAttempts is how many times an invalid JSON commit message is re-prompted.
+	LLMStructuredRepairAttempts = 2
+
+	// LLMRefineHistoryTurns is how many previous refinements are kept as conversation history.
+	LLMRefineHistoryTurns = 3
 )
 
 // LLM provider hosts (OpenAI-compatible APIs only).
@@ -73,6 +122,15 @@ const (
 	LLMHostMistral    = "https://api.mistral.ai"
 )
 
+// Azure OpenAI settings.
+const (
+	// LLMPathAzure is the deployment-based chat completions path, formatted with the deployment name.
+	LLMPathAzure = "/openai/deployments/%s/chat/completions"
+
+	// LLMAzureDefaultAPIVersion is the api-version query parameter used when not configured.
+	LLMAzureDefaultAPIVersion = "2024-10-21"
+)
+
 // LLM API paths for chat completions.
 const (
 	LLMPathOllama     = "/api/generate"
@@ -81,11 +139,13 @@ const (
 	LLMPathGroq       = "/openai/v1/chat/completions" // Groq
 )
 
-// LLM language options.
+// LLM language options. Any language code (e.g. "ja", "pt-br") or bilingual pair
+// joined by LLMLangPairSep (e.g. "en+ja") is accepted.
 const (
 	LLMLangEN        = "en"
 	LLMLangZH        = "zh"
-	LLMLangBilingual = "bilingual"
+	LLMLangBilingual = "bilingual" // alias of en+zh
+	LLMLangPairSep   = "+"
 )
 
 // LLM default models.
@@ -99,8 +159,9 @@ const (
 	// LLMDefaultFilePrompt is the system prompt for analyzing individual file diffs.
 	LLMDefaultFilePrompt = "You are a git diff analyzer. Output only a brief summary, no formatting."
 
-	// LLMCommitPromptEN is the system prompt for generating commit messages in English.
-	LLMCommitPromptEN = `You are a git commit message generator. Generate EXACTLY ONE commit message following the Angular commit convention.
+	// LLMCommitPrompt is the system prompt for generating commit messages in a single language.
+	// %[1]s is the language name, %[2]s extra subject rules for the language.
+	LLMCommitPrompt = `You are a git commit message generator. Generate EXACTLY ONE commit message following the Angular commit convention.
 
 FORMAT (strict):
 <type>(<scope>): <subject>
@@ -109,45 +170,121 @@ FORMAT (strict):
 
 RULES:
 1. type: REQUIRED, one of: feat, fix, docs, style, refactor, test, chore, perf, hotfix
-2. scope: REQUIRED, a short word describing the affected area (e.g., api, ui, config, auth, db, cli)
-3. subject: REQUIRED, imperative mood, lowercase, no period, max 50 chars
-4. body: REQUIRED, 3-5 bullet points starting with "- ", each point starts with a verb
+2. scope: REQUIRED, a short English word describing the affected area (e.g., api, ui, config, auth, db, cli)
+3. subject: REQUIRED, written in %[1]s, imperative mood%[2]s, no period, max 50 chars
+4. body: REQUIRED, 3-5 bullet points starting with "- ", written in %[1]s, each point starts with a verb
 
 OUTPUT ONLY THE COMMIT MESSAGE. No explanation, no markdown, no code blocks.`
 
-	// LLMCommitPromptZH is the system prompt for generating commit messages in Chinese.
-	LLMCommitPromptZH = `你是一个 git commit 消息生成器.请严格按照 Angular commit 规范生成一条 commit 消息.
+	// LLMCommitPromptBilingual is the system prompt for generating commit messages with a bilingual subject.
+	// %[1]s is the primary language name, %[2]s the secondary language name.
+	LLMCommitPromptBilingual = `You are a git commit message generator. Generate EXACTLY ONE commit message following the Angular commit convention with bilingual subject.
+
+FORMAT (strict):
+<type>(<scope>): <subject in %[1]s> (<subject in %[2]s>)
 
-格式要求（严格遵守）:
-<type>(<scope>): <中文描述>
+<body in %[2]s>
 
-<正文>
+RULES:
+1. type: REQUIRED, one of: feat, fix, docs, style, refactor, test, chore, perf, hotfix
+2. scope: REQUIRED, a short English word describing the affected area (e.g., api, ui, config, auth, db, cli)
+3. subject: REQUIRED, the %[1]s description followed by its %[2]s translation in parentheses, imperative mood, no period
+4. body: REQUIRED, 3-5 bullet points starting with "- ", written in %[2]s
 
-规则:
-1. type: 必填, 只能是: feat, fix, docs, style, refactor, test, chore, perf, hotfix
-2. scope: 必填, 描述影响范围的英文单词（如 api, ui, config, auth, db, cli）
-3. subject: 必填, 使用中文描述, 不加句号, 最多50字
-4. body: 必填, 3-5个要点, 每行以"- "开头, 使用中文描述
+OUTPUT ONLY THE COMMIT MESSAGE. No explanation, no markdown, no code blocks.`
 
-只输出 commit 消息本身, 不要任何解释, markdown 或代码块.`
+	// LLMCommitJSONPrompt is the system prompt for structured commit messages in a single language.
+	// %[1]s is the language name, %[2]s extra subject rules for the language,
+	// %[3]s the comma-separated commit types and %[4]d the maximum subject length.
+	LLMCommitJSONPrompt = `You are a git commit message generator. Generate EXACTLY ONE commit message following the Angular commit convention.
 
-	// LLMCommitPromptBilingual is the system prompt for generating bilingual commit messages.
-	LLMCommitPromptBilingual = `You are a git commit message generator. Generate EXACTLY ONE commit message following the Angular commit convention with bilingual subject.
+FORMAT (strict): a single JSON object with this schema
+{"type": "<type>", "scope": "<scope>", "subject": "<subject>", "body": ["<bullet point>", "..."]}
 
-FORMAT (strict):
-<type>(<scope>): <english subject> (<中文描述>)
+RULES:
+1. type: REQUIRED, one of: %[3]s
+2. scope: REQUIRED, a short English word describing the affected area (e.g., api, ui, config, auth, db, cli)
+3. subject: REQUIRED, written in %[1]s, imperative mood%[2]s, no period, max %[4]d chars
+4. body: REQUIRED, 3-5 bullet points without a leading dash, written in %[1]s, each point starts with a verb
+
+OUTPUT ONLY THE JSON OBJECT. No explanation, no markdown, no code blocks.`
+
+	// LLMCommitJSONPromptBilingual is the system prompt for structured commit messages with a bilingual subject.
+	// %[1]s is the primary language name, %[2]s the secondary language name,
+	// %[3]s the comma-separated commit types and %[4]d the maximum subject length.
+	LLMCommitJSONPromptBilingual = `You are a git commit message generator. Generate EXACTLY ONE commit message following the Angular commit convention with bilingual subject.
 
-<body in Chinese>
+FORMAT (strict): a single JSON object with this schema
+{"type": "<type>", "scope": "<scope>", "subject": "<subject in %[1]s> (<subject in %[2]s>)", "body": ["<bullet point in %[2]s>", "..."]}
 
 RULES:
-1. type: REQUIRED, one of: feat, fix, docs, style, refactor, test, chore, perf, hotfix
-2. scope: REQUIRED, a short word describing the affected area (e.g., api, ui, config, auth, db, cli)
-3. subject: REQUIRED, format "english description (中文描述)", lowercase English, no period
-4. body: REQUIRED, 3-5 bullet points starting with "- ", written in Chinese
+1. type: REQUIRED, one of: %[3]s
+2. scope: REQUIRED, a short English word describing the affected area (e.g., api, ui, config, auth, db, cli)
+3. subject: REQUIRED, the %[1]s description followed by its %[2]s translation in parentheses, imperative mood, no period, max %[4]d chars in total
+4. body: REQUIRED, 3-5 bullet points without a leading dash, written in %[2]s
 
-OUTPUT ONLY THE COMMIT MESSAGE. No explanation, no markdown, no code blocks.`
+OUTPUT ONLY THE JSON OBJECT. No explanation, no markdown, no code blocks.`
 )
 
+// LLMExampleInput is the file summary list used for the few-shot commit message example.
+const LLMExampleInput = `- auth.go: Added JWT validation
+- user.go: Added profile endpoint
+- docs.md: Updated API docs`
+
+// LLMPRPrompt is the system prompt for generating pull request titles and descriptions.
+const LLMPRPrompt = `You are a helpful assistant that writes pull request descriptions from commits and code changes.
+
+Output format:
+- First line: the PR title in the form "type(scope): summary", max 72 characters, no trailing period
+- One blank line
+- A Markdown body with exactly these sections:
+
+## Summary
+1-3 sentences on what the change does and why.
+
+## Changes
+Bullet points grouped under "### <type>" headings (e.g. ### feat, ### fix), only for types that apply.
+
+## Testing
+How the change was tested or should be tested; mention added or updated tests.
+
+Output only the title and body, no code fences or extra commentary.`
+
+// LLMBranchPrompt is the system prompt for suggesting a branch name from a task description.
+const LLMBranchPrompt = `You are a git branch naming assistant. Given a task description, suggest a branch.
+
+Respond with a single line in the form type/slug, nothing else:
+- type: one of feat, fix, docs, style, refactor, test, chore, perf, hotfix
+- slug: 2-5 lowercase English words joined by hyphens, ASCII only, max 40 characters
+
+Example:
+Input: users can't reset password when email has plus sign
+Output: fix/password-reset-plus-email`
+
+// LLMReviewPrompt is the system prompt for reviewing a single file diff.
+const LLMReviewPrompt = `You are a careful code reviewer. Review only the added and changed lines of the git diff.
+
+Report real problems only: bugs, security issues, data loss, race conditions, missing error handling, leftover debug code.
+Do not comment on style or formatting, and do not praise the code.
+
+Output one finding per line in the form:
+[high|medium|low] description (mention the line or identifier)
+
+If there are no problems, output exactly: NONE`
+
+// LLMRefinePrompt is the follow-up turn asking the model to revise its last commit message.
+// The %s placeholder is the user's instruction.
+const LLMRefinePrompt = `Revise the commit message above according to this instruction: %s
+
+Keep the type(scope): subject format and the same language rules. Output only the revised commit message.`
+
+// LLMCommitJSONInstruction is appended to a custom commit prompt when requesting structured output;
+// the built-in prompts have JSON variants instead. The language and style rules of the custom
+// prompt still apply to subject and body.
+// %[1]s is the comma-separated list of CommitTypes names, %[2]d the maximum subject length.
+const LLMCommitJSONInstruction = `Respond with a single JSON object only, no markdown, using this schema:
+{"type": "<one of: %[1]s>", "scope": "<short area>", "subject": "<subject, max %[2]d chars, no trailing period>", "body": ["<bullet point without leading dash>", "..."]}`
+
 // Binary and path constants.
 const (
 	// BinaryName is the name of the main binary.
@@ -161,6 +298,12 @@ const (
 
 	// TempFilePrefix is the prefix for temporary files.
 	TempFilePrefix = "gitflow"
+
+	// IgnoreFileName is the per-repository file listing paths excluded from AI analysis.
+	IgnoreFileName = ".gitflowignore"
+
+	// LLMCacheDirName is the directory under the user cache dir holding cached LLM responses.
+	LLMCacheDirName = "gitflow-toolkit/llm"
 )
 
 // SymlinkCommands returns all symlink command names (without git- prefix).
@@ -168,6 +311,8 @@ func SymlinkCommands() []string {
 	return []string{
 		CmdCommit,
 		CmdPush,
+		CmdPRDesc,
+		CmdReview,
 		Feat,
 		Fix,
 		Docs,
@@ -11,6 +11,16 @@ type FileDiff struct {
 	Diff string
 }
 
+// IsBinary reports whether the diff describes a binary file change.
+func (f FileDiff) IsBinary() bool {
+	for _, line := range strings.Split(f.Diff, "\n") {
+		if strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch" {
+			return true
+		}
+	}
+	return false
+}
+
 // GetStagedDiff returns the staged diff with specified context lines.
 func GetStagedDiff(context int) (string, error) {
 	return Run("diff", "--staged", fmt.Sprintf("-U%d", context))
@@ -55,6 +65,32 @@ func GetPreviousCommits(n int) ([]string, error) {
 	return commits, nil
 }
 
+// MainBranch returns the detected main branch name.
+func MainBranch() string {
+	return getMainBranch()
+}
+
+// GetBranchCommits returns the subjects of commits on HEAD that are not on base, oldest first.
+func GetBranchCommits(base string) ([]string, error) {
+	output, err := Run("log", "--reverse", "--no-merges", "--pretty=format:%s", base+"..HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			commits = append(commits, line)
+		}
+	}
+	return commits, nil
+}
+
+// GetBranchDiff returns the combined diff of HEAD since it diverged from base.
+func GetBranchDiff(base string, context int) (string, error) {
+	return Run("diff", fmt.Sprintf("-U%d", context), base+"...HEAD")
+}
+
 // getMainBranch detects the main branch name.
 func getMainBranch() string {
 	// Try to get from remote HEAD
@@ -124,6 +160,33 @@ func SplitDiffByFile(diff string) []FileDiff {
 	return result
 }
 
+// SplitDiffHunks splits a single file diff into its header and hunks.
+// The header contains everything before the first "@@" line; each hunk
+// starts with its "@@" line.
+func SplitDiffHunks(diff string) (header string, hunks []string) {
+	var headerLines []string
+	var current []string
+
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "@@") {
+			if current != nil {
+				hunks = append(hunks, strings.Join(current, "\n"))
+			}
+			current = []string{line}
+		} else if current != nil {
+			current = append(current, line)
+		} else {
+			headerLines = append(headerLines, line)
+		}
+	}
+
+	if current != nil {
+		hunks = append(hunks, strings.Join(current, "\n"))
+	}
+
+	return strings.Join(headerLines, "\n"), hunks
+}
+
 // extractFilePath extracts the file path from a diff header line.
 // Input: "diff --git a/path/to/file b/path/to/file"
 // Output: "path/to/file"
@@ -121,3 +121,62 @@ func TestExtractFilePath(t *testing.T) {
 		})
 	}
 }
+
+func TestFileDiffIsBinary(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+		want bool
+	}{
+		{
+			name: "text diff",
+			diff: "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n+line",
+			want: false,
+		},
+		{
+			name: "binary diff",
+			diff: "diff --git a/logo.png b/logo.png\nindex 1234567..abcdefg 100644\nBinary files a/logo.png and b/logo.png differ",
+			want: true,
+		},
+		{
+			name: "binary patch",
+			diff: "diff --git a/logo.png b/logo.png\nGIT binary patch\nliteral 42",
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (FileDiff{Diff: tt.diff}).IsBinary(); got != tt.want {
+				t.Errorf("IsBinary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitDiffHunks(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,2 +1,3 @@
+ package main
++import "fmt"
+@@ -10,1 +11,2 @@
+ func main() {
++	fmt.Println()`
+
+	header, hunks := SplitDiffHunks(diff)
+
+	wantHeader := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go"
+	if header != wantHeader {
+		t.Errorf("header = %q, want %q", header, wantHeader)
+	}
+
+	wantHunks := []string{
+		"@@ -1,2 +1,3 @@\n package main\n+import \"fmt\"",
+		"@@ -10,1 +11,2 @@\n func main() {\n+\tfmt.Println()",
+	}
+	if !reflect.DeepEqual(hunks, wantHunks) {
+		t.Errorf("hunks = %q, want %q", hunks, wantHunks)
+	}
+}
@@ -2,6 +2,7 @@
 package git
 
 import (
+	"bytes"
 	"errors"
 	"os"
 	"os/exec"
@@ -22,6 +23,37 @@ var (
 
 // Run executes a git command with the given arguments.
 func Run(args ...string) (string, error) {
+	bs, err := command(args...).CombinedOutput()
+	if err != nil {
+		if bs != nil {
+			return "", errors.New(strings.TrimSpace(string(bs)))
+		}
+		return "", err
+	}
+
+	return strings.TrimSpace(string(bs)), nil
+}
+
+// RunRaw executes a git command with stdin as input and returns the untrimmed stdout.
+// Used for plumbing commands where whitespace in the output is significant.
+func RunRaw(stdin []byte, args ...string) ([]byte, error) {
+	cmd := command(args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	bs, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+	return bs, nil
+}
+
+// command creates the git command for the current platform.
+func command(args ...string) *exec.Cmd {
 	var cmd *exec.Cmd
 	if runtime.GOOS == "windows" {
 		cmd = exec.Command("git.exe", args...)
@@ -33,16 +65,7 @@ func Run(args ...string) (string, error) {
 	if !config.GetBool(config.GitConfigSSHStrictHostKey, false) {
 		cmd.Env = append(os.Environ(), "GIT_SSH_COMMAND=ssh -o StrictHostKeyChecking=no")
 	}
-
-	bs, err := cmd.CombinedOutput()
-	if err != nil {
-		if bs != nil {
-			return "", errors.New(strings.TrimSpace(string(bs)))
-		}
-		return "", err
-	}
-
-	return strings.TrimSpace(string(bs)), nil
+	return cmd
 }
 
 // RepoCheck checks if the current directory is a git repository.
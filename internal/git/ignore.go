@@ -0,0 +1,128 @@
+package git
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/mritd/gitflow-toolkit/v3/config"
+	"github.com/mritd/gitflow-toolkit/v3/consts"
+)
+
+// GetIgnorePatterns returns the AI ignore patterns from gitconfig and the
+// repository's .gitflowignore file (if present).
+// The gitconfig value may contain multiple patterns separated by commas or whitespace.
+func GetIgnorePatterns() []string {
+	var patterns []string
+
+	if val := config.GetString(config.GitConfigLLMIgnore, ""); val != "" {
+		patterns = append(patterns, strings.FieldsFunc(val, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})...)
+	}
+
+	if root, err := Run("rev-parse", "--show-toplevel"); err == nil {
+		if data, err := os.ReadFile(filepath.Join(root, consts.IgnoreFileName)); err == nil {
+			patterns = append(patterns, strings.Split(string(data), "\n")...)
+		}
+	}
+
+	return patterns
+}
+
+// FilterIgnored splits files into those kept for analysis and the paths excluded by patterns.
+func FilterIgnored(files []FileDiff, patterns []string) (kept []FileDiff, ignored []string) {
+	if len(patterns) == 0 {
+		return files, nil
+	}
+	for _, f := range files {
+		if MatchIgnore(patterns, f.Path) {
+			ignored = append(ignored, f.Path)
+		} else {
+			kept = append(kept, f)
+		}
+	}
+	return kept, ignored
+}
+
+// MatchIgnore reports whether the path is excluded by gitignore-style patterns.
+// Supports comments (#), negation (!), directory patterns (trailing /),
+// anchored patterns (containing /), and the ** wildcard. The last matching pattern wins.
+func MatchIgnore(patterns []string, filePath string) bool {
+	segs := strings.Split(strings.Trim(filePath, "/"), "/")
+
+	ignored := false
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" || strings.HasPrefix(p, "#") {
+			continue
+		}
+
+		negate := strings.HasPrefix(p, "!")
+		if negate {
+			p = p[1:]
+		}
+
+		dirOnly := strings.HasSuffix(p, "/")
+		p = strings.TrimSuffix(p, "/")
+		if p == "" {
+			continue
+		}
+
+		if matchPattern(p, dirOnly, segs) {
+			ignored = !negate
+		}
+	}
+	return ignored
+}
+
+// matchPattern matches a single pattern against path segments.
+// A pattern matching a parent directory also matches everything under it.
+func matchPattern(pattern string, dirOnly bool, segs []string) bool {
+	// Patterns without a slash match a name at any depth
+	if !strings.Contains(pattern, "/") {
+		for i, seg := range segs {
+			isDir := i < len(segs)-1
+			if (isDir || !dirOnly) && matchSegment(pattern, seg) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Patterns with a slash are anchored to the repository root
+	patSegs := strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+	for k := 1; k <= len(segs); k++ {
+		isDir := k < len(segs)
+		if (isDir || !dirOnly) && matchSegments(patSegs, segs[:k]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches pattern segments against path segments, where "**" matches zero or more segments.
+func matchSegments(pat, segs []string) bool {
+	if len(pat) == 0 {
+		return len(segs) == 0
+	}
+	if pat[0] == "**" {
+		for i := 0; i <= len(segs); i++ {
+			if matchSegments(pat[1:], segs[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segs) == 0 || !matchSegment(pat[0], segs[0]) {
+		return false
+	}
+	return matchSegments(pat[1:], segs[1:])
+}
+
+// matchSegment matches a single path segment using shell glob syntax.
+func matchSegment(pattern, name string) bool {
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
@@ -0,0 +1,54 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchIgnore(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		want     bool
+	}{
+		{"no patterns", nil, "main.go", false},
+		{"exact name", []string{"go.sum"}, "go.sum", true},
+		{"name at any depth", []string{"go.sum"}, "tools/go.sum", true},
+		{"glob", []string{"*.snap"}, "ui/__snapshots__/button.snap", true},
+		{"directory", []string{"vendor/"}, "vendor/github.com/x/y.go", true},
+		{"directory pattern does not match file", []string{"vendor/"}, "vendor", false},
+		{"anchored", []string{"/docs/*.md"}, "docs/readme.md", true},
+		{"anchored not nested", []string{"/docs/*.md"}, "sub/docs/readme.md", false},
+		{"double star", []string{"**/testdata/**"}, "pkg/a/testdata/in.json", true},
+		{"comment ignored", []string{"# go.sum"}, "go.sum", false},
+		{"negation", []string{"*.json", "!config.json"}, "config.json", false},
+		{"negation then re-ignore", []string{"*.json", "!config.json", "config.json"}, "config.json", true},
+		{"no match", []string{"*.lock"}, "main.go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchIgnore(tt.patterns, tt.path); got != tt.want {
+				t.Errorf("MatchIgnore(%v, %q) = %v, want %v", tt.patterns, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterIgnored(t *testing.T) {
+	files := []FileDiff{
+		{Path: "main.go"},
+		{Path: "go.sum"},
+		{Path: "vendor/x/y.go"},
+	}
+
+	kept, ignored := FilterIgnored(files, []string{"go.sum", "vendor/"})
+
+	if want := []FileDiff{{Path: "main.go"}}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("kept = %v, want %v", kept, want)
+	}
+	if want := []string{"go.sum", "vendor/x/y.go"}; !reflect.DeepEqual(ignored, want) {
+		t.Errorf("ignored = %v, want %v", ignored, want)
+	}
+}
@@ -1,10 +1,15 @@
 package git
 
 import (
+	"crypto/sha1"
 	"errors"
 	"fmt"
+	"math"
 	"os/exec"
+	"runtime"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/mritd/gitflow-toolkit/v3/consts"
 	"github.com/mritd/gitflow-toolkit/v3/config"
@@ -24,6 +29,55 @@ func GetLuckyPrefix() string {
 	return config.GetString(config.GitConfigLuckyCommitPrefix, "")
 }
 
+// GetLuckyTimeout returns how long to search for a lucky hash before keeping the
+// original commit. Returns 0 if there is no timeout.
+func GetLuckyTimeout() time.Duration {
+	return config.GetDuration(config.GitConfigLuckyCommitTimeout, consts.LuckyCommitDefaultTimeout)
+}
+
+// LuckyExpectedAttempts returns the average number of hashes needed to find a
+// hash starting with prefix (16^len).
+func LuckyExpectedAttempts(prefix string) float64 {
+	return math.Pow(16, float64(len(prefix)))
+}
+
+// MeasureHashRate hashes a commit-sized object on every CPU for d and returns
+// the measured SHA-1 hashes per second.
+func MeasureHashRate(d time.Duration) float64 {
+	sample := []byte(fmt.Sprintf("commit 240\x00tree %s\nparent %s\nauthor a <a@b> 0 +0000\ncommitter a <a@b> 0 +0000\n\n%s\n",
+		strings.Repeat("0", 40), strings.Repeat("0", 40), strings.Repeat("x", 72)))
+
+	workers := runtime.NumCPU()
+	counts := make([]uint64, workers)
+	deadline := time.Now().Add(d)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			buf := append([]byte(nil), sample...)
+			var n uint64
+			for time.Now().Before(deadline) {
+				// Check the clock every 1024 hashes to keep overhead low
+				for i := 0; i < 1024; i++ {
+					buf[len(buf)-2] = byte(n)
+					sha1.Sum(buf)
+					n++
+				}
+			}
+			counts[w] = n
+		}(w)
+	}
+	wg.Wait()
+
+	var total uint64
+	for _, n := range counts {
+		total += n
+	}
+	return float64(total) / d.Seconds()
+}
+
 // ValidateLuckyPrefix validates and normalizes the prefix.
 // Returns lowercase prefix or error if invalid.
 func ValidateLuckyPrefix(prefix string) (string, error) {
@@ -36,10 +90,8 @@ func ValidateLuckyPrefix(prefix string) (string, error) {
 	}
 
 	prefix = strings.ToLower(prefix)
-	for _, c := range prefix {
-		if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f')) {
-			return "", ErrLuckyPrefixInvalid
-		}
+	if !isHex(prefix) {
+		return "", ErrLuckyPrefixInvalid
 	}
 
 	return prefix, nil
@@ -55,11 +107,6 @@ func CheckLuckyCommit() error {
 	return nil
 }
 
-// LuckyCommitCmd creates an exec.Cmd for running lucky_commit with the given prefix.
-func LuckyCommitCmd(prefix string) *exec.Cmd {
-	return exec.Command(consts.LuckyCommitBinary, prefix)
-}
-
 // GetHeadHash returns the current HEAD commit hash.
 func GetHeadHash() (string, error) {
 	return Run("rev-parse", "HEAD")
@@ -0,0 +1,222 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/mritd/gitflow-toolkit/v3/config"
+	"github.com/mritd/gitflow-toolkit/v3/consts"
+)
+
+// ErrLuckySigned is returned when the HEAD commit has a signature the padding would invalidate.
+var ErrLuckySigned = errors.New("signed commits cannot be rewritten by the built-in search, set gitflow.lucky-commit-engine to external")
+
+// luckyPaddingBits is the number of whitespace characters appended to the commit
+// message; each one is a space or a tab, giving 2^bits candidate hashes.
+const luckyPaddingBits = 64
+
+// luckyBatch is the number of attempts between cancellation checks and counter updates.
+const luckyBatch = 4096
+
+// GetLuckyEngine returns the configured lucky commit engine (builtin or external).
+func GetLuckyEngine() string {
+	if config.GetString(config.GitConfigLuckyCommitEngine, consts.LuckyEngineBuiltin) == consts.LuckyEngineExternal {
+		return consts.LuckyEngineExternal
+	}
+	return consts.LuckyEngineBuiltin
+}
+
+// LuckySearch searches for a HEAD commit hash starting with a prefix.
+type LuckySearch struct {
+	prefix   string
+	attempts atomic.Uint64
+}
+
+// NewLuckySearch creates a search for prefix. The prefix must be validated.
+func NewLuckySearch(prefix string) *LuckySearch {
+	return &LuckySearch{prefix: prefix}
+}
+
+// Attempts returns the number of hashes tried so far.
+func (s *LuckySearch) Attempts() uint64 {
+	return s.attempts.Load()
+}
+
+// Run rewrites the HEAD commit with whitespace padding at the end of the message
+// until its hash starts with the prefix, using every CPU, then points HEAD at it.
+// The original commit is kept if ctx is cancelled first.
+func (s *LuckySearch) Run(ctx context.Context) error {
+	content, err := RunRaw(nil, "cat-file", "commit", "HEAD")
+	if err != nil {
+		return fmt.Errorf("failed to read HEAD commit: %w", err)
+	}
+	if isSignedCommit(content) {
+		return ErrLuckySigned
+	}
+
+	oldHash, err := GetHeadHash()
+	if err != nil {
+		return err
+	}
+
+	newHash := sha1.New
+	if format, err := Run("rev-parse", "--show-object-format"); err == nil && format == "sha256" {
+		newHash = sha256.New
+	}
+
+	lucky, err := searchLuckyPadding(ctx, content, s.prefix, newHash, runtime.NumCPU(), &s.attempts)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	out, err := RunRaw(lucky, "hash-object", "-t", "commit", "-w", "--stdin")
+	if err != nil {
+		return fmt.Errorf("failed to write lucky commit: %w", err)
+	}
+	hash := string(bytes.TrimSpace(out))
+	if !hasHexPrefix(hash, s.prefix) {
+		return fmt.Errorf("lucky commit hash %s does not match prefix %s", hash, s.prefix)
+	}
+
+	if _, err := Run("update-ref", "-m", "lucky commit: "+s.prefix, "HEAD", hash, oldHash); err != nil {
+		return fmt.Errorf("failed to update HEAD: %w", err)
+	}
+	return nil
+}
+
+// ExternalLuckySearch returns a search running the lucky_commit binary,
+// terminated with SIGTERM when ctx is cancelled.
+func ExternalLuckySearch(prefix string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		cmd := exec.CommandContext(ctx, consts.LuckyCommitBinary, prefix)
+		cmd.Cancel = func() error {
+			return cmd.Process.Signal(syscall.SIGTERM)
+		}
+		return cmd.Run()
+	}
+}
+
+// searchLuckyPadding appends a line of spaces and tabs to the commit content and
+// searches the combinations in parallel for a git object hash starting with prefix.
+// Returns the new commit content.
+func searchLuckyPadding(ctx context.Context, content []byte, prefix string, newHash func() hash.Hash, workers int, attempts *atomic.Uint64) ([]byte, error) {
+	padded := prefix
+	if len(padded)%2 == 1 {
+		padded += "0" // hex.DecodeString needs whole bytes
+	}
+	want, err := hex.DecodeString(padded)
+	if err != nil {
+		return nil, err
+	}
+	nibbles := len(prefix)
+
+	// The object header and original content are the same for every attempt,
+	// so hash them once and resume from the saved state
+	size := len(content) + luckyPaddingBits + 1
+	h := newHash()
+	h.Write([]byte("commit " + strconv.Itoa(size) + "\x00"))
+	h.Write(content)
+	state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	search, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		once   sync.Once
+		result []byte
+		wg     sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			h := newHash()
+			restore := h.(encoding.BinaryUnmarshaler)
+			tail := make([]byte, luckyPaddingBits+1)
+			tail[luckyPaddingBits] = '\n'
+			var sum []byte
+
+			for n := uint64(w); ; {
+				for i := 0; i < luckyBatch; i++ {
+					luckyPadding(tail, n)
+					_ = restore.UnmarshalBinary(state)
+					h.Write(tail)
+					sum = h.Sum(sum[:0])
+					if matchNibbles(sum, want, nibbles) {
+						once.Do(func() {
+							result = append(append([]byte(nil), content...), tail...)
+							cancel()
+						})
+						return
+					}
+					n += uint64(workers)
+				}
+				attempts.Add(luckyBatch)
+				if search.Err() != nil {
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	// Workers only stop early on a match or cancellation; 2^64 attempts are never exhausted
+	if result == nil {
+		return nil, ctx.Err()
+	}
+	return result, nil
+}
+
+// luckyPadding writes n as spaces (0) and tabs (1) into the padding bytes.
+func luckyPadding(tail []byte, n uint64) {
+	for i := 0; i < luckyPaddingBits; i++ {
+		if n&(1<<i) != 0 {
+			tail[i] = '\t'
+		} else {
+			tail[i] = ' '
+		}
+	}
+}
+
+// matchNibbles reports whether the first nibbles hex digits of sum equal want.
+func matchNibbles(sum, want []byte, nibbles int) bool {
+	full := nibbles / 2
+	if !bytes.Equal(sum[:full], want[:full]) {
+		return false
+	}
+	if nibbles%2 == 1 {
+		return sum[full]>>4 == want[full]>>4
+	}
+	return true
+}
+
+// hasHexPrefix reports whether hash starts with prefix.
+func hasHexPrefix(hash, prefix string) bool {
+	return len(hash) >= len(prefix) && hash[:len(prefix)] == prefix
+}
+
+// isSignedCommit reports whether the raw commit object has a signature header.
+func isSignedCommit(content []byte) bool {
+	header, _, _ := bytes.Cut(content, []byte("\n\n"))
+	return bytes.Contains(append([]byte("\n"), header...), []byte("\ngpgsig"))
+}
@@ -0,0 +1,103 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"sync/atomic"
+	"testing"
+)
+
+const testCommit = "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n" +
+	"author A <a@example.com> 1700000000 +0000\n" +
+	"committer A <a@example.com> 1700000000 +0000\n\n" +
+	"feat(lucky): test commit\n"
+
+func TestSearchLuckyPadding(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  string
+		newHash func() hash.Hash
+	}{
+		{"sha1 even", "ab", sha1.New},
+		{"sha1 odd", "c0f", sha1.New},
+		{"sha256", "7e", sha256.New},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Uint64
+			got, err := searchLuckyPadding(context.Background(), []byte(testCommit), tt.prefix, tt.newHash, 4, &attempts)
+			if err != nil {
+				t.Fatalf("searchLuckyPadding() error = %v", err)
+			}
+
+			if !bytes.HasPrefix(got, []byte(testCommit)) {
+				t.Fatal("original content should be kept")
+			}
+			padding := got[len(testCommit):]
+			if len(padding) != luckyPaddingBits+1 || len(bytes.Trim(padding, " \t\n")) != 0 {
+				t.Errorf("padding = %q, want %d spaces/tabs and a newline", padding, luckyPaddingBits)
+			}
+
+			h := tt.newHash()
+			fmt.Fprintf(h, "commit %d\x00", len(got))
+			h.Write(got)
+			if sum := hex.EncodeToString(h.Sum(nil)); !hasHexPrefix(sum, tt.prefix) {
+				t.Errorf("object hash %s does not start with %s", sum, tt.prefix)
+			}
+		})
+	}
+}
+
+func TestSearchLuckyPaddingCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var attempts atomic.Uint64
+	// 12 hex digits are practically never found within the first batch
+	_, err := searchLuckyPadding(ctx, []byte(testCommit), "000000000000", sha1.New, 2, &attempts)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("searchLuckyPadding() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestMatchNibbles(t *testing.T) {
+	sum := []byte{0xab, 0xcd, 0xef}
+	tests := []struct {
+		want    []byte
+		nibbles int
+		match   bool
+	}{
+		{[]byte{0xab}, 2, true},
+		{[]byte{0xab, 0xc0}, 3, true},
+		{[]byte{0xab, 0xd0}, 3, false},
+		{[]byte{0xa0}, 1, true},
+		{[]byte{0xab, 0xcd}, 4, true},
+		{[]byte{0xab, 0xce}, 4, false},
+	}
+
+	for _, tt := range tests {
+		if got := matchNibbles(sum, tt.want, tt.nibbles); got != tt.match {
+			t.Errorf("matchNibbles(%x, %d) = %v, want %v", tt.want, tt.nibbles, got, tt.match)
+		}
+	}
+}
+
+func TestIsSignedCommit(t *testing.T) {
+	signed := "tree abc\ngpgsig -----BEGIN PGP SIGNATURE-----\n \n -----END PGP SIGNATURE-----\n\nmsg\n"
+	if !isSignedCommit([]byte(signed)) {
+		t.Error("commit with gpgsig header should be signed")
+	}
+	if isSignedCommit([]byte(testCommit)) {
+		t.Error("plain commit should not be signed")
+	}
+	if isSignedCommit([]byte("tree abc\n\nmention gpgsig in message\n")) {
+		t.Error("gpgsig in the message should not count")
+	}
+}
@@ -0,0 +1,225 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mritd/gitflow-toolkit/v3/consts"
+)
+
+// LuckyTemplate is a parsed lucky-commit-prefix. Besides hex characters it may
+// contain placeholders expanded for every commit:
+//
+//	{date}       commit date, default format yymm (e.g. 2610)
+//	{date:FMT}   date with the tokens yyyy, yy, mm and dd (e.g. {date:yymmdd})
+//	{counter}    number of commits on the current branch, including the new one
+//	{counter:N}  counter zero-padded or cut to its last N digits
+//	{type}       commit type in hex speak (feat -> fea7, fix -> f1, test -> 7e57)
+//	{type:N}     type hex speak cut to N characters
+type LuckyTemplate struct {
+	parts []luckyPart
+}
+
+// luckyPart is a literal hex string or a placeholder.
+type luckyPart struct {
+	literal string
+	name    string
+	arg     string
+}
+
+// LuckyContext holds the values placeholders are expanded from.
+type LuckyContext struct {
+	Type    string
+	Time    time.Time
+	Counter func() (int, error)
+}
+
+// hexSpeak maps letters to similar looking hex digits; other letters are dropped.
+var hexSpeak = strings.NewReplacer(
+	"o", "0", "i", "1", "l", "1", "z", "2", "s", "5", "t", "7", "g", "9",
+)
+
+// ParseLuckyTemplate parses a lucky-commit-prefix value.
+func ParseLuckyTemplate(s string) (*LuckyTemplate, error) {
+	if s == "" {
+		return nil, ErrLuckyPrefixEmpty
+	}
+
+	t := &LuckyTemplate{}
+	for s != "" {
+		open := strings.IndexByte(s, '{')
+		if open < 0 {
+			open = len(s)
+		}
+		if open > 0 {
+			literal := strings.ToLower(s[:open])
+			if !isHex(literal) {
+				return nil, ErrLuckyPrefixInvalid
+			}
+			t.parts = append(t.parts, luckyPart{literal: literal})
+			s = s[open:]
+			continue
+		}
+
+		end := strings.IndexByte(s, '}')
+		if end < 0 {
+			return nil, fmt.Errorf("invalid lucky commit prefix: unclosed placeholder %q", s)
+		}
+		name, arg, _ := strings.Cut(s[1:end], ":")
+		part := luckyPart{name: name, arg: arg}
+		if err := part.validate(); err != nil {
+			return nil, err
+		}
+		t.parts = append(t.parts, part)
+		s = s[end+1:]
+	}
+	return t, nil
+}
+
+// Static reports whether the template has no placeholders.
+func (t *LuckyTemplate) Static() bool {
+	for _, p := range t.parts {
+		if p.name != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate expands the template with sample values, the given time and counter
+// and every commit type, so prefixes that can only be invalid or too long are
+// reported before committing.
+func (t *LuckyTemplate) Validate(now time.Time, counter int) error {
+	if t.Static() {
+		_, err := t.Expand(LuckyContext{})
+		return err
+	}
+	for _, ct := range consts.CommitTypes {
+		ctx := LuckyContext{
+			Type:    ct.Name,
+			Time:    now,
+			Counter: func() (int, error) { return counter, nil },
+		}
+		if _, err := t.Expand(ctx); err != nil {
+			return fmt.Errorf("%w (expanded for a %s commit)", err, ct.Name)
+		}
+	}
+	return nil
+}
+
+// Expand expands the placeholders and validates the resulting prefix.
+func (t *LuckyTemplate) Expand(ctx LuckyContext) (string, error) {
+	var sb strings.Builder
+	for _, p := range t.parts {
+		if p.name == "" {
+			sb.WriteString(p.literal)
+			continue
+		}
+		value, err := p.expand(ctx)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(value)
+	}
+	return ValidateLuckyPrefix(sb.String())
+}
+
+func (p luckyPart) validate() error {
+	switch p.name {
+	case "date":
+		if p.arg == "" {
+			return nil
+		}
+		if rest := dateTokens.Replace(p.arg); strings.Trim(rest, "\x00") != "" {
+			return fmt.Errorf("invalid lucky commit prefix: date format %q may only use yyyy, yy, mm and dd", p.arg)
+		}
+		return nil
+	case "counter", "type":
+		if p.arg == "" {
+			return nil
+		}
+		if n, err := strconv.Atoi(p.arg); err != nil || n <= 0 {
+			return fmt.Errorf("invalid lucky commit prefix: {%s:%s} needs a positive length", p.name, p.arg)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid lucky commit prefix: unknown placeholder {%s}", p.name)
+	}
+}
+
+// dateTokens is used to check that a date format only contains known tokens.
+var dateTokens = strings.NewReplacer("yyyy", "\x00", "yy", "\x00", "mm", "\x00", "dd", "\x00")
+
+func (p luckyPart) expand(ctx LuckyContext) (string, error) {
+	switch p.name {
+	case "date":
+		format := p.arg
+		if format == "" {
+			format = "yymm"
+		}
+		return strings.NewReplacer(
+			"yyyy", ctx.Time.Format("2006"),
+			"yy", ctx.Time.Format("06"),
+			"mm", ctx.Time.Format("01"),
+			"dd", ctx.Time.Format("02"),
+		).Replace(format), nil
+
+	case "counter":
+		if ctx.Counter == nil {
+			return "", fmt.Errorf("invalid lucky commit prefix: {counter} is not available")
+		}
+		n, err := ctx.Counter()
+		if err != nil {
+			return "", fmt.Errorf("failed to count commits: %w", err)
+		}
+		value := strconv.Itoa(n)
+		if width, _ := strconv.Atoi(p.arg); width > 0 {
+			value = fmt.Sprintf("%0*d", width, n)
+			value = value[len(value)-width:]
+		}
+		return value, nil
+
+	case "type":
+		value := typeHexSpeak(ctx.Type)
+		if value == "" {
+			return "", fmt.Errorf("invalid lucky commit prefix: commit type %q has no hex form", ctx.Type)
+		}
+		if n, _ := strconv.Atoi(p.arg); n > 0 && len(value) > n {
+			value = value[:n]
+		}
+		return value, nil
+	}
+	return "", fmt.Errorf("invalid lucky commit prefix: unknown placeholder {%s}", p.name)
+}
+
+// typeHexSpeak converts a commit type to hex speak (feat -> fea7).
+func typeHexSpeak(commitType string) string {
+	var sb strings.Builder
+	for _, c := range hexSpeak.Replace(strings.ToLower(commitType)) {
+		if (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') {
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
+
+// isHex reports whether s only contains lowercase hex characters.
+func isHex(s string) bool {
+	for _, c := range s {
+		if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f')) {
+			return false
+		}
+	}
+	return true
+}
+
+// CommitCount returns the number of commits reachable from HEAD.
+func CommitCount() (int, error) {
+	out, err := Run("rev-list", "--count", "HEAD")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(out)
+}
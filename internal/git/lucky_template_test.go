@@ -0,0 +1,131 @@
+package git
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseLuckyTemplate(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantStatic bool
+		wantErr    bool
+	}{
+		{"static", "ABC", true, false},
+		{"date", "{date}", false, false},
+		{"date format", "c0{date:yymmdd}", false, false},
+		{"counter width", "{counter:4}", false, false},
+		{"type", "{type}0", false, false},
+		{"empty", "", false, true},
+		{"non-hex literal", "xyz{date}", false, true},
+		{"unknown placeholder", "{branch}", false, true},
+		{"unclosed", "ab{date", false, true},
+		{"bad date format", "{date:hh}", false, true},
+		{"bad width", "{counter:0}", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseLuckyTemplate(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLuckyTemplate(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if err == nil && tmpl.Static() != tt.wantStatic {
+				t.Errorf("Static() = %v, want %v", tmpl.Static(), tt.wantStatic)
+			}
+		})
+	}
+}
+
+func TestLuckyTemplateExpand(t *testing.T) {
+	ctx := LuckyContext{
+		Type:    "feat",
+		Time:    time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+		Counter: func() (int, error) { return 42, nil },
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		ctx     LuckyContext
+		want    string
+		wantErr bool
+	}{
+		{"static", "ABC", ctx, "abc", false},
+		{"date default", "{date}", ctx, "2610", false},
+		{"date format", "{date:yyyymmdd}", ctx, "20261019", false},
+		{"counter", "c{counter}", ctx, "c42", false},
+		{"counter padded", "{counter:4}", ctx, "0042", false},
+		{"counter cut", "{counter:1}", ctx, "2", false},
+		{"type feat", "{type}", ctx, "fea7", false},
+		{"type cut", "{type:2}0", ctx, "fe0", false},
+		{"combined", "{type}{date}", ctx, "fea72610", false},
+		{"too long after expansion", "{date:yyyymmdd}{date:yyyymmdd}", ctx, "", true},
+		{"counter error", "{counter}", LuckyContext{Counter: func() (int, error) { return 0, errors.New("no commits") }}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseLuckyTemplate(tt.input)
+			if err != nil {
+				t.Fatalf("ParseLuckyTemplate(%q) error = %v", tt.input, err)
+			}
+			got, err := tmpl.Expand(tt.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Expand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLuckyTemplateValidate(t *testing.T) {
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input   string
+		counter int
+		wantErr bool
+	}{
+		{"abc", 1, false},
+		{"{type}{date}", 1, false},
+		{"{counter:4}", 123456, false},
+		{"{date:yyyymmdd}{counter}", 9999, false},
+		{"{date:yyyymmdd}{counter}", 10000, true},
+		{"{date:yyyymmdd}{date:yyyymmdd}", 1, true},
+		{"{type}{type}{type}", 1, true},
+	}
+
+	for _, tt := range tests {
+		tmpl, err := ParseLuckyTemplate(tt.input)
+		if err != nil {
+			t.Fatalf("ParseLuckyTemplate(%q) error = %v", tt.input, err)
+		}
+		if err := tmpl.Validate(now, tt.counter); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%q, %d) error = %v, wantErr %v", tt.input, tt.counter, err, tt.wantErr)
+		}
+	}
+}
+
+func TestTypeHexSpeak(t *testing.T) {
+	tests := map[string]string{
+		"feat":     "fea7",
+		"fix":      "f1",
+		"docs":     "d0c5",
+		"style":    "571e",
+		"refactor": "efac70",
+		"test":     "7e57",
+		"chore":    "c0e",
+		"perf":     "ef",
+		"hotfix":   "07f1",
+	}
+
+	for in, want := range tests {
+		if got := typeHexSpeak(in); got != want {
+			t.Errorf("typeHexSpeak(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
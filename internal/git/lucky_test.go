@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"testing"
+	"time"
 
 	"github.com/mritd/gitflow-toolkit/v3/config"
 )
@@ -75,3 +76,26 @@ func TestGetLuckyPrefix(t *testing.T) {
 		t.Errorf("GetLuckyPrefix() = %v, want empty", prefix)
 	}
 }
+
+func TestLuckyExpectedAttempts(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   float64
+	}{
+		{"a", 16},
+		{"abc", 4096},
+		{"0000000", 268435456},
+	}
+
+	for _, tt := range tests {
+		if got := LuckyExpectedAttempts(tt.prefix); got != tt.want {
+			t.Errorf("LuckyExpectedAttempts(%q) = %v, want %v", tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestMeasureHashRate(t *testing.T) {
+	if rate := MeasureHashRate(20 * time.Millisecond); rate <= 0 {
+		t.Errorf("MeasureHashRate() = %v, want > 0", rate)
+	}
+}
@@ -0,0 +1,134 @@
+package git
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// FileStatus is a changed file in the working tree.
+type FileStatus struct {
+	Path      string
+	Index     byte // status in the index, ' ' if unchanged (see git status --porcelain)
+	WorkTree  byte // status in the working tree, ' ' if unchanged
+	Untracked bool
+}
+
+// Staged reports whether the file has staged changes.
+func (f FileStatus) Staged() bool {
+	return !f.Untracked && f.Index != ' '
+}
+
+// Unstaged reports whether the file has changes that are not staged.
+func (f FileStatus) Unstaged() bool {
+	return f.Untracked || f.WorkTree != ' '
+}
+
+// RepoRoot returns the top-level directory of the repository.
+func RepoRoot() (string, error) {
+	return Run("rev-parse", "--show-toplevel")
+}
+
+// RepoPath returns the absolute path of a path relative to the repository root,
+// as reported by WorkingTreeStatus.
+func RepoPath(path string) (string, error) {
+	root, err := RepoRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, filepath.FromSlash(path)), nil
+}
+
+// runAtRoot runs a git command from the repository root, so paths reported by
+// git status resolve the same way from any subdirectory.
+func runAtRoot(stdin []byte, args ...string) ([]byte, error) {
+	root, err := RepoRoot()
+	if err != nil {
+		return nil, err
+	}
+	return RunRaw(stdin, append([]string{"-C", root}, args...)...)
+}
+
+// WorkingTreeStatus returns the changed and untracked files, with paths
+// relative to the repository root.
+func WorkingTreeStatus() ([]FileStatus, error) {
+	out, err := runAtRoot(nil, "status", "--porcelain=v1", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+	return parseStatus(string(out)), nil
+}
+
+// parseStatus parses the output of git status --porcelain=v1 -z.
+func parseStatus(out string) []FileStatus {
+	var files []FileStatus
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		f := FileStatus{Path: entry[3:], Index: entry[0], WorkTree: entry[1]}
+		switch {
+		case entry[:2] == "??":
+			f.Untracked = true
+		case entry[:2] == "!!":
+			continue
+		case f.Index == 'R' || f.Index == 'C':
+			// Renames and copies are followed by the original path
+			i++
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// StageFile stages all changes of a file.
+func StageFile(path string) error {
+	_, err := runAtRoot(nil, "add", "--", path)
+	return err
+}
+
+// UnstageFile removes all staged changes of a file from the index.
+func UnstageFile(path string) error {
+	_, err := runAtRoot(nil, "reset", "-q", "--", path)
+	return err
+}
+
+// StageAll stages all changes including untracked files.
+func StageAll() error {
+	_, err := runAtRoot(nil, "add", "--all")
+	return err
+}
+
+// patchDiffArgs make git diff output a patch git apply accepts, whatever the
+// user's color, external diff and prefix settings are.
+var patchDiffArgs = []string{"diff", "--no-color", "--no-ext-diff", "--src-prefix=a/", "--dst-prefix=b/"}
+
+// GetFileDiff returns the staged or unstaged diff of a file, keeping the
+// trailing newline so hunks can be applied again.
+func GetFileDiff(path string, staged bool) (string, error) {
+	args := append([]string{}, patchDiffArgs...)
+	if staged {
+		args = append(args, "--staged")
+	}
+	out, err := runAtRoot(nil, append(args, "--", path)...)
+	return string(out), err
+}
+
+// StageHunk stages a single hunk of a file diff. The header is the diff
+// header returned by SplitDiffHunks.
+func StageHunk(header, hunk string) error {
+	_, err := runAtRoot([]byte(hunkPatch(header, hunk)), "apply", "--cached", "-")
+	return err
+}
+
+// UnstageHunk removes a single hunk of a staged file diff from the index.
+func UnstageHunk(header, hunk string) error {
+	_, err := runAtRoot([]byte(hunkPatch(header, hunk)), "apply", "--cached", "--reverse", "-")
+	return err
+}
+
+// hunkPatch builds a patch applying one hunk.
+func hunkPatch(header, hunk string) string {
+	return strings.TrimRight(header, "\n") + "\n" + strings.TrimRight(hunk, "\n") + "\n"
+}
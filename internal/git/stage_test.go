@@ -0,0 +1,180 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want []FileStatus
+	}{
+		{
+			name: "empty",
+			out:  "",
+			want: nil,
+		},
+		{
+			name: "staged, modified and untracked",
+			out:  "M  staged.go\x00 M modified.go\x00MM both.go\x00?? new.go\x00",
+			want: []FileStatus{
+				{Path: "staged.go", Index: 'M', WorkTree: ' '},
+				{Path: "modified.go", Index: ' ', WorkTree: 'M'},
+				{Path: "both.go", Index: 'M', WorkTree: 'M'},
+				{Path: "new.go", Index: '?', WorkTree: '?', Untracked: true},
+			},
+		},
+		{
+			name: "rename skips the original path",
+			out:  "R  new name.go\x00old name.go\x00 D gone.go\x00",
+			want: []FileStatus{
+				{Path: "new name.go", Index: 'R', WorkTree: ' '},
+				{Path: "gone.go", Index: ' ', WorkTree: 'D'},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseStatus(tt.out); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseStatus() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileStatusStaged(t *testing.T) {
+	tests := []struct {
+		status       FileStatus
+		wantStaged   bool
+		wantUnstaged bool
+	}{
+		{status: FileStatus{Index: 'M', WorkTree: ' '}, wantStaged: true},
+		{status: FileStatus{Index: ' ', WorkTree: 'M'}, wantUnstaged: true},
+		{status: FileStatus{Index: 'A', WorkTree: 'M'}, wantStaged: true, wantUnstaged: true},
+		{status: FileStatus{Index: '?', WorkTree: '?', Untracked: true}, wantUnstaged: true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.status.Staged(); got != tt.wantStaged {
+			t.Errorf("%+v Staged() = %v, want %v", tt.status, got, tt.wantStaged)
+		}
+		if got := tt.status.Unstaged(); got != tt.wantUnstaged {
+			t.Errorf("%+v Unstaged() = %v, want %v", tt.status, got, tt.wantUnstaged)
+		}
+	}
+}
+
+func TestHunkPatch(t *testing.T) {
+	header := "diff --git a/a.txt b/a.txt\n--- a/a.txt\n+++ b/a.txt"
+	hunk := "@@ -1 +1 @@\n-1\n+1x\n"
+	want := "diff --git a/a.txt b/a.txt\n--- a/a.txt\n+++ b/a.txt\n@@ -1 +1 @@\n-1\n+1x\n"
+	if got := hunkPatch(header, hunk); got != want {
+		t.Errorf("hunkPatch() = %q, want %q", got, want)
+	}
+}
+
+// initStageRepo creates a repository with a committed file in a subdirectory
+// and changes the working directory to that subdirectory.
+func initStageRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	sub := filepath.Join(root, "sub")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, "f.txt"), []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	t.Chdir(sub)
+	return root
+}
+
+func TestStagingFromSubdirectory(t *testing.T) {
+	root := initStageRepo(t)
+	// Settings that would break the patches built from the file diff
+	t.Setenv("GIT_CONFIG_COUNT", "3")
+	t.Setenv("GIT_CONFIG_KEY_0", "color.diff")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+	t.Setenv("GIT_CONFIG_KEY_1", "diff.noprefix")
+	t.Setenv("GIT_CONFIG_VALUE_1", "true")
+	t.Setenv("GIT_CONFIG_KEY_2", "diff.mnemonicPrefix")
+	t.Setenv("GIT_CONFIG_VALUE_2", "true")
+	if err := os.WriteFile(filepath.Join(root, "sub", "f.txt"), []byte("1x\n2\n3\n4\n5\n6\n7\n8\n9\n10x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := WorkingTreeStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []FileStatus{{Path: "sub/f.txt", Index: ' ', WorkTree: 'M'}}
+	if !reflect.DeepEqual(files, want) {
+		t.Fatalf("WorkingTreeStatus() = %+v, want %+v", files, want)
+	}
+
+	diff, err := GetFileDiff("sub/f.txt", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, hunks := SplitDiffHunks(diff)
+	if len(hunks) != 2 {
+		t.Fatalf("SplitDiffHunks() = %d hunks, want 2", len(hunks))
+	}
+
+	if err := StageHunk(header, hunks[0]); err != nil {
+		t.Fatalf("StageHunk() error = %v", err)
+	}
+	staged, err := GetFileDiff("sub/f.txt", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(staged, "+1x") || strings.Contains(staged, "+10x") {
+		t.Errorf("staged diff after StageHunk() = %q, want only the first hunk", staged)
+	}
+
+	if err := UnstageFile("sub/f.txt"); err != nil {
+		t.Fatalf("UnstageFile() error = %v", err)
+	}
+	if err := StageFile("sub/f.txt"); err != nil {
+		t.Fatalf("StageFile() error = %v", err)
+	}
+	files, err = WorkingTreeStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []FileStatus{{Path: "sub/f.txt", Index: 'M', WorkTree: ' '}}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("WorkingTreeStatus() after StageFile() = %+v, want %+v", files, want)
+	}
+
+	path, err := RepoPath("sub/f.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("RepoPath() = %q: %v", path, err)
+	}
+}
@@ -0,0 +1,163 @@
+package llm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/mritd/gitflow-toolkit/v3/config"
+	"github.com/mritd/gitflow-toolkit/v3/consts"
+)
+
+// Cache stores LLM responses on disk, one file per key.
+// Entries older than the TTL are ignored and removed; when the total size exceeds
+// the limit, the oldest entries are removed first.
+type Cache struct {
+	dir      string
+	ttl      time.Duration
+	maxBytes int64
+}
+
+// NewCache creates a cache in dir. A ttl or maxBytes of 0 disables that limit.
+func NewCache(dir string, ttl time.Duration, maxBytes int64) *Cache {
+	return &Cache{dir: dir, ttl: ttl, maxBytes: maxBytes}
+}
+
+// NewDefaultCache creates a cache under the user cache dir using the configured limits.
+// Returns nil if caching is disabled via llm-cache or no cache dir is available.
+func NewDefaultCache() *Cache {
+	if !config.GetBool(config.GitConfigLLMCache, true) {
+		return nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return nil
+	}
+	ttl := config.GetDuration(config.GitConfigLLMCacheTTL, consts.LLMDefaultCacheTTL)
+	maxMB := config.GetInt(config.GitConfigLLMCacheMaxMB, consts.LLMDefaultCacheMaxMB)
+	return NewCache(filepath.Join(base, consts.LLMCacheDirName), ttl, int64(maxMB)<<20)
+}
+
+// CacheKey returns a stable key for the given parts (e.g. model, system prompt, user prompt).
+func CacheKey(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get returns the cached value for key, or false if missing or expired.
+func (c *Cache) Get(key string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	path := c.path(key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", false
+	}
+	if c.expired(info, time.Now()) {
+		_ = os.Remove(path)
+		return "", false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// Put stores value under key and enforces the cache limits.
+func (c *Cache) Put(key, value string) error {
+	if c == nil {
+		return nil
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+
+	// Write to a temp file and rename so concurrent readers never see partial entries
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.WriteString(value); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	c.prune()
+	return nil
+}
+
+// prune removes expired entries, then the oldest entries until the size limit is met.
+func (c *Cache) prune() {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	type file struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	now := time.Now()
+	var files []file
+	var total int64
+	for _, e := range entries {
+		if e.IsDir() || len(e.Name()) != sha256.Size*2 {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(c.dir, e.Name())
+		if c.expired(info, now) {
+			_ = os.Remove(path)
+			continue
+		}
+		files = append(files, file{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+
+	if c.maxBytes <= 0 || total <= c.maxBytes {
+		return
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+	for _, f := range files {
+		if total <= c.maxBytes {
+			break
+		}
+		if os.Remove(f.path) == nil {
+			total -= f.size
+		}
+	}
+}
+
+func (c *Cache) expired(info os.FileInfo, now time.Time) bool {
+	return c.ttl > 0 && now.Sub(info.ModTime()) > c.ttl
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key)
+}
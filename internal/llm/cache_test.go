@@ -0,0 +1,94 @@
+package llm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCacheKey(t *testing.T) {
+	if CacheKey("model", "prompt") != CacheKey("model", "prompt") {
+		t.Error("CacheKey() should be stable")
+	}
+	if CacheKey("model", "prompt") == CacheKey("model", "other") {
+		t.Error("CacheKey() should differ for different inputs")
+	}
+	// Part boundaries must matter
+	if CacheKey("ab", "c") == CacheKey("a", "bc") {
+		t.Error("CacheKey() should separate parts")
+	}
+}
+
+func TestCache_GetPut(t *testing.T) {
+	c := NewCache(t.TempDir(), time.Hour, 0)
+	key := CacheKey("model", "prompt")
+
+	if _, ok := c.Get(key); ok {
+		t.Fatal("Get() on empty cache should miss")
+	}
+	if err := c.Put(key, "summary"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	got, ok := c.Get(key)
+	if !ok || got != "summary" {
+		t.Errorf("Get() = %q, %v, want %q, true", got, ok, "summary")
+	}
+}
+
+func TestCache_TTL(t *testing.T) {
+	dir := t.TempDir()
+	c := NewCache(dir, time.Hour, 0)
+	key := CacheKey("model", "prompt")
+	if err := c.Put(key, "summary"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, key), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := c.Get(key); ok {
+		t.Error("Get() should miss expired entry")
+	}
+	if _, err := os.Stat(filepath.Join(dir, key)); !os.IsNotExist(err) {
+		t.Error("expired entry should be removed")
+	}
+}
+
+func TestCache_MaxSize(t *testing.T) {
+	dir := t.TempDir()
+	c := NewCache(dir, 0, 25)
+
+	keys := []string{CacheKey("1"), CacheKey("2"), CacheKey("3")}
+	for i, key := range keys {
+		if err := c.Put(key, strings.Repeat("x", 10)); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+		// Distinct modification times, oldest first
+		mt := time.Now().Add(time.Duration(i-len(keys)) * time.Minute)
+		_ = os.Chtimes(filepath.Join(dir, key), mt, mt)
+	}
+	c.prune()
+
+	if _, ok := c.Get(keys[0]); ok {
+		t.Error("oldest entry should be evicted")
+	}
+	for _, key := range keys[1:] {
+		if _, ok := c.Get(key); !ok {
+			t.Error("newer entries should be kept")
+		}
+	}
+}
+
+func TestCache_Nil(t *testing.T) {
+	var c *Cache
+	if _, ok := c.Get("key"); ok {
+		t.Error("nil cache Get() should miss")
+	}
+	if err := c.Put("key", "value"); err != nil {
+		t.Errorf("nil cache Put() error = %v", err)
+	}
+}
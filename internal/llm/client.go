@@ -1,5 +1,5 @@
 // Package llm provides a unified HTTP client for LLM APIs.
-// Supports Ollama (local) and OpenAI-compatible APIs (OpenRouter, Groq, OpenAI).
+// Supports Ollama (local), OpenAI-compatible APIs (OpenRouter, Groq, OpenAI) and Azure OpenAI.
 //
 // Configuration priority: gitconfig > environment variable > default value
 //
@@ -15,9 +15,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -33,29 +35,67 @@ const (
 	ProviderGroq       Provider = "groq"
 	ProviderOpenRouter Provider = "openrouter"
 	ProviderOpenAI     Provider = "openai"
+	ProviderAzure      Provider = "azure"
 )
 
 // Client is an LLM API client supporting multiple providers.
 type Client struct {
-	provider              Provider
-	host                  string
-	apiPath               string
-	apiKey                string
-	timeout               time.Duration
-	retries               int
-	lang                  string
-	model                 string
-	temperature           float64
-	filePrompt            string
-	commitPromptEN        string
-	commitPromptZH        string
-	commitPromptBilingual string
+	provider     Provider
+	host         string
+	apiPath      string
+	apiKey       string
+	apiVersion   string
+	timeout      time.Duration
+	retries      int
+	lang         Language
+	model        string
+	temperature  float64
+	filePrompt   string
+	commitPrompt string
 }
 
 // GenerateOptions configures a generation request.
 type GenerateOptions struct {
 	System      string
 	Temperature float64
+	JSON        bool   // request a JSON object response (response_format / Ollama format)
+	History     []Turn // previous conversation turns sent before the prompt
+}
+
+// Conversation roles used in Turn.
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Turn is a single message of a previous conversation turn.
+type Turn struct {
+	Role    string
+	Content string
+}
+
+// StatusError is returned when the API responds with a non-200 status.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, e.Body)
+}
+
+// IsUnsupportedError reports whether the error indicates the API rejected the request
+// parameters (e.g., JSON output mode not supported by the provider or model).
+func IsUnsupportedError(err error) bool {
+	var se *StatusError
+	if !errors.As(err, &se) {
+		return false
+	}
+	switch se.StatusCode {
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity, http.StatusNotImplemented:
+		return true
+	}
+	return false
 }
 
 // NewClient creates a new LLM client from gitconfig.
@@ -68,6 +108,9 @@ type GenerateOptions struct {
 //  1. User-defined llm-api-path takes highest priority
 //  2. Auto-detect from host for known providers
 //  3. Fall back to OpenAI-compatible path (/v1/chat/completions)
+//
+// Azure OpenAI hosts use a deployment-based path built from llm-azure-deployment
+// (defaults to the model name) and send llm-azure-api-version as a query parameter.
 func NewClient() *Client {
 	// Get API key from gitconfig
 	apiKey := config.GetString(config.GitConfigLLMAPIKey, "")
@@ -100,54 +143,64 @@ func NewClient() *Client {
 
 	// Get user-defined API path (highest priority)
 	apiPath := config.GetString(config.GitConfigLLMAPIPath, "")
-	if apiPath == "" {
+	if apiPath == "" && provider != ProviderAzure {
 		apiPath = defaultPath
 	}
 
 	// Get model
 	model := config.GetString(config.GitConfigLLMModel, defaultModel)
 
+	// Azure routes requests by deployment name instead of model
+	var apiVersion string
+	if provider == ProviderAzure {
+		apiVersion = config.GetString(config.GitConfigLLMAzureAPIVersion, consts.LLMAzureDefaultAPIVersion)
+		if apiPath == "" {
+			deployment := config.GetString(config.GitConfigLLMAzureDeployment, model)
+			apiPath = azurePath(deployment)
+		}
+	}
+
 	// Get other settings
 	timeout := config.GetDuration(config.GitConfigLLMRequestTimeout, consts.LLMDefaultRequestTimeout)
 	retries := config.GetInt(config.GitConfigLLMMaxRetries, consts.LLMDefaultRetries)
 	temperature := config.GetFloat(config.GitConfigLLMTemperature, consts.LLMDefaultTemperature)
 
 	// Get language (validate value)
-	lang := config.GetString(config.GitConfigLLMOutputLang, consts.LLMDefaultLang)
-	switch lang {
-	case consts.LLMLangEN, consts.LLMLangZH, consts.LLMLangBilingual:
-		// valid
-	default:
-		lang = consts.LLMDefaultLang
+	lang, ok := ParseLanguage(config.GetString(config.GitConfigLLMOutputLang, consts.LLMDefaultLang))
+	if !ok {
+		lang = DefaultLanguage
 	}
 
-	// Get custom prompts
+	// Get custom prompts (pairs fall back to the generic bilingual prompt)
 	filePrompt := config.GetString(config.GitConfigLLMFileAnalysisPrompt, "")
-	commitPromptEN := config.GetString(config.GitConfigLLMCommitPromptEN, "")
-	commitPromptZH := config.GetString(config.GitConfigLLMCommitPromptZH, "")
-	commitPromptBilingual := config.GetString(config.GitConfigLLMCommitPromptBilingual, "")
+	commitPrompt := config.GetString(lang.CommitPromptKey(), "")
+	if commitPrompt == "" && lang.Bilingual() {
+		commitPrompt = config.GetString(config.GitConfigLLMCommitPromptBilingual, "")
+	}
 
 	return &Client{
-		provider:              provider,
-		host:                  host,
-		apiPath:               apiPath,
-		apiKey:                apiKey,
-		timeout:               timeout,
-		retries:               retries,
-		lang:                  lang,
-		model:                 model,
-		temperature:           temperature,
-		filePrompt:            filePrompt,
-		commitPromptEN:        commitPromptEN,
-		commitPromptZH:        commitPromptZH,
-		commitPromptBilingual: commitPromptBilingual,
+		provider:     provider,
+		host:         host,
+		apiPath:      apiPath,
+		apiKey:       apiKey,
+		apiVersion:   apiVersion,
+		timeout:      timeout,
+		retries:      retries,
+		lang:         lang,
+		model:        model,
+		temperature:  temperature,
+		filePrompt:   filePrompt,
+		commitPrompt: commitPrompt,
 	}
 }
 
 // detectProvider detects the LLM provider from host and returns the provider type and default API path.
-// For unknown hosts, returns OpenRouter provider with OpenAI-compatible path.
+// For unknown hosts, returns OpenAI provider with OpenAI-compatible path.
+// Azure OpenAI returns an empty path since it depends on the deployment name.
 func detectProvider(host string) (Provider, string) {
 	switch {
+	case strings.Contains(host, ".openai.azure.com"), strings.Contains(host, ".cognitiveservices.azure.com"):
+		return ProviderAzure, ""
 	case strings.Contains(host, "groq.com"):
 		return ProviderGroq, consts.LLMPathGroq
 	case strings.Contains(host, "openai.com"):
@@ -217,6 +270,7 @@ type ollamaRequest struct {
 	Prompt  string         `json:"prompt"`
 	System  string         `json:"system,omitempty"`
 	Stream  bool           `json:"stream"`
+	Format  string         `json:"format,omitempty"`
 	Options *ollamaOptions `json:"options,omitempty"`
 }
 
@@ -229,6 +283,11 @@ type ollamaResponse struct {
 }
 
 func (c *Client) doGenerateOllama(ctx context.Context, model, prompt string, opt GenerateOptions) (string, error) {
+	// The generate endpoint has no message list, so history is inlined into the prompt
+	if len(opt.History) > 0 {
+		prompt = flattenHistory(opt.History, prompt)
+	}
+
 	reqBody := ollamaRequest{
 		Model:  model,
 		Prompt: prompt,
@@ -236,6 +295,10 @@ func (c *Client) doGenerateOllama(ctx context.Context, model, prompt string, opt
 		Stream: false,
 	}
 
+	if opt.JSON {
+		reqBody.Format = "json"
+	}
+
 	if opt.Temperature > 0 {
 		reqBody.Options = &ollamaOptions{Temperature: opt.Temperature}
 	}
@@ -262,7 +325,7 @@ func (c *Client) doGenerateOllama(ctx context.Context, model, prompt string, opt
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(respBody))
+		return "", &StatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var result ollamaResponse
@@ -273,12 +336,31 @@ func (c *Client) doGenerateOllama(ctx context.Context, model, prompt string, opt
 	return strings.TrimSpace(result.Response), nil
 }
 
+// flattenHistory renders previous turns and the new prompt as a single transcript.
+func flattenHistory(history []Turn, prompt string) string {
+	var sb strings.Builder
+	for _, turn := range history {
+		role := "User"
+		if turn.Role == RoleAssistant {
+			role = "Assistant"
+		}
+		sb.WriteString(role + ":\n" + turn.Content + "\n\n")
+	}
+	sb.WriteString("User:\n" + prompt + "\n\nAssistant:\n")
+	return sb.String()
+}
+
 // OpenAI-compatible API types (works with OpenRouter, Groq, OpenAI)
 type openAIRequest struct {
-	Model       string          `json:"model"`
-	Messages    []openAIMessage `json:"messages"`
-	Temperature float64         `json:"temperature,omitempty"`
-	MaxTokens   int             `json:"max_tokens,omitempty"`
+	Model          string                `json:"model"`
+	Messages       []openAIMessage       `json:"messages"`
+	Temperature    float64               `json:"temperature,omitempty"`
+	MaxTokens      int                   `json:"max_tokens,omitempty"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+}
+
+type openAIResponseFormat struct {
+	Type string `json:"type"`
 }
 
 type openAIMessage struct {
@@ -300,12 +382,15 @@ type openAIError struct {
 }
 
 func (c *Client) doGenerateOpenAI(ctx context.Context, model, prompt string, opt GenerateOptions) (string, error) {
-	messages := make([]openAIMessage, 0, 2)
+	messages := make([]openAIMessage, 0, len(opt.History)+2)
 
 	if opt.System != "" {
 		messages = append(messages, openAIMessage{Role: "system", Content: opt.System})
 	}
-	messages = append(messages, openAIMessage{Role: "user", Content: prompt})
+	for _, turn := range opt.History {
+		messages = append(messages, openAIMessage{Role: turn.Role, Content: turn.Content})
+	}
+	messages = append(messages, openAIMessage{Role: RoleUser, Content: prompt})
 
 	reqBody := openAIRequest{
 		Model:     model,
@@ -317,6 +402,10 @@ func (c *Client) doGenerateOpenAI(ctx context.Context, model, prompt string, opt
 		reqBody.Temperature = opt.Temperature
 	}
 
+	if opt.JSON {
+		reqBody.ResponseFormat = &openAIResponseFormat{Type: "json_object"}
+	}
+
 	body, err := json.Marshal(reqBody)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal request: %w", err)
@@ -325,12 +414,16 @@ func (c *Client) doGenerateOpenAI(ctx context.Context, model, prompt string, opt
 	ctx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.host+c.apiPath, bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(), bytes.NewReader(body))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if c.provider == ProviderAzure {
+		req.Header.Set("api-key", c.apiKey)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
@@ -340,7 +433,7 @@ func (c *Client) doGenerateOpenAI(ctx context.Context, model, prompt string, opt
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(respBody))
+		return "", &StatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var result openAIResponse
@@ -359,14 +452,45 @@ func (c *Client) doGenerateOpenAI(ctx context.Context, model, prompt string, opt
 	return strings.TrimSpace(result.Choices[0].Message.Content), nil
 }
 
+// azurePath returns the chat completions path of an Azure deployment.
+func azurePath(deployment string) string {
+	return fmt.Sprintf(consts.LLMPathAzure, url.PathEscape(deployment))
+}
+
+// endpoint returns the full chat completions URL for OpenAI-compatible providers.
+// Azure requires the api-version query parameter on every request; it is added
+// to any query of a custom API path unless that already sets it.
+func (c *Client) endpoint() string {
+	endpoint := c.host + c.apiPath
+	if c.provider != ProviderAzure || c.apiVersion == "" {
+		return endpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	q := u.Query()
+	if !q.Has("api-version") {
+		q.Set("api-version", c.apiVersion)
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
 // GetModel returns the configured model name.
 func (c *Client) GetModel() string {
 	return c.model
 }
 
-// GetLang returns the configured language.
-func (c *Client) GetLang() string {
-	return c.lang
+// GetTemperature returns the configured default temperature.
+func (c *Client) GetTemperature() float64 {
+	return c.temperature
+}
+
+// GetLang returns the configured output language.
+func (c *Client) GetLang() Language {
+	return c.lang.orDefault()
 }
 
 // GetFilePrompt returns the custom file analysis prompt, or empty string for default.
@@ -374,17 +498,10 @@ func (c *Client) GetFilePrompt() string {
 	return c.filePrompt
 }
 
-// GetCommitPrompt returns the custom commit generation prompt for the specified language,
+// GetCommitPrompt returns the custom commit generation prompt for the configured language,
 // or empty string to use the default prompt.
-func (c *Client) GetCommitPrompt(lang string) string {
-	switch lang {
-	case consts.LLMLangZH:
-		return c.commitPromptZH
-	case consts.LLMLangBilingual:
-		return c.commitPromptBilingual
-	default:
-		return c.commitPromptEN
-	}
+func (c *Client) GetCommitPrompt() string {
+	return c.commitPrompt
 }
 
 // GetDiffContext returns the configured diff context lines.
@@ -396,3 +513,28 @@ func GetDiffContext() int {
 func GetConcurrency() int {
 	return config.GetInt(config.GitConfigLLMMaxConcurrency, consts.LLMDefaultConcurrency)
 }
+
+// GetMaxFileTokens returns the configured token budget for a single file analysis request.
+// Larger file diffs are split into hunk groups that each fit within this budget.
+func GetMaxFileTokens() int {
+	return config.GetInt(config.GitConfigLLMMaxFileTokens, consts.LLMDefaultMaxFileTokens)
+}
+
+// GetSinglePassTokens returns the total diff size (estimated tokens) below which the whole
+// diff is sent in a single request instead of per-file analysis. 0 disables single-pass mode.
+func GetSinglePassTokens() int {
+	return config.GetInt(config.GitConfigLLMSinglePassTokens, consts.LLMDefaultSinglePass)
+}
+
+// GetCandidates returns how many alternative commit messages to generate,
+// clamped to [1, LLMMaxCandidates].
+func GetCandidates() int {
+	n := config.GetInt(config.GitConfigLLMCandidates, consts.LLMDefaultCandidates)
+	if n < 1 {
+		return 1
+	}
+	if n > consts.LLMMaxCandidates {
+		return consts.LLMMaxCandidates
+	}
+	return n
+}
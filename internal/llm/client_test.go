@@ -3,6 +3,8 @@ package llm
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -47,6 +49,8 @@ func TestDetectProvider(t *testing.T) {
 		{"deepseek", "https://api.deepseek.com", ProviderOpenAI, consts.LLMPathOpenAI},
 		{"mistral", "https://api.mistral.ai", ProviderOpenAI, consts.LLMPathOpenAI},
 		{"openrouter", "https://openrouter.ai", ProviderOpenRouter, consts.LLMPathOpenRouter},
+		{"azure", "https://my-resource.openai.azure.com", ProviderAzure, ""},
+		{"azure cognitive services", "https://my-resource.cognitiveservices.azure.com", ProviderAzure, ""},
 		{"unknown", "https://custom-llm.example.com", ProviderOpenAI, consts.LLMPathOpenAI},
 	}
 
@@ -242,6 +246,85 @@ func TestGenerate_OpenAI(t *testing.T) {
 	})
 }
 
+func TestGenerate_Azure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments/gpt-4o/chat/completions" {
+			t.Errorf("path = %s, want /openai/deployments/gpt-4o/chat/completions", r.URL.Path)
+		}
+		if v := r.URL.Query().Get("api-version"); v != "2024-10-21" {
+			t.Errorf("api-version = %s, want 2024-10-21", v)
+		}
+		if key := r.Header.Get("api-key"); key != "test-key" {
+			t.Errorf("api-key = %s, want test-key", key)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Authorization = %s, want empty", auth)
+		}
+
+		resp := openAIResponse{
+			Choices: []openAIChoice{{
+				Message: openAIMessage{Role: "assistant", Content: "generated text"},
+			}},
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	c := &Client{
+		provider:   ProviderAzure,
+		host:       server.URL,
+		apiPath:    "/openai/deployments/gpt-4o/chat/completions",
+		apiKey:     "test-key",
+		apiVersion: "2024-10-21",
+		timeout:    10 * time.Second,
+	}
+
+	result, err := c.Generate(context.Background(), "gpt-4o", "test prompt")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if result != "generated text" {
+		t.Errorf("Generate() = %q, want %q", result, "generated text")
+	}
+}
+
+func TestAzureEndpoint(t *testing.T) {
+	if got, want := azurePath("my deployment/v2"), "/openai/deployments/my%20deployment%2Fv2/chat/completions"; got != want {
+		t.Errorf("azurePath() = %q, want %q", got, want)
+	}
+
+	tests := []struct {
+		name    string
+		apiPath string
+		want    string
+	}{
+		{
+			name:    "deployment path",
+			apiPath: azurePath("gpt 4o"),
+			want:    "https://r.openai.azure.com/openai/deployments/gpt%204o/chat/completions?api-version=2024-10-21",
+		},
+		{
+			name:    "custom path with query",
+			apiPath: "/openai/v1/chat/completions?foo=bar",
+			want:    "https://r.openai.azure.com/openai/v1/chat/completions?api-version=2024-10-21&foo=bar",
+		},
+		{
+			name:    "custom path with api-version",
+			apiPath: "/openai/v1/chat/completions?api-version=preview",
+			want:    "https://r.openai.azure.com/openai/v1/chat/completions?api-version=preview",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{provider: ProviderAzure, host: "https://r.openai.azure.com", apiPath: tt.apiPath, apiVersion: "2024-10-21"}
+			if got := c.endpoint(); got != tt.want {
+				t.Errorf("endpoint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetDiffContext(t *testing.T) {
 	// NOTE: This test may be affected by ~/.gitconfig settings.
 	if ctx := config.GetString(config.GitConfigLLMDiffContext, ""); ctx != "" {
@@ -254,3 +337,134 @@ func TestGetDiffContext(t *testing.T) {
 		}
 	})
 }
+
+func TestGenerate_JSONMode(t *testing.T) {
+	t.Run("openai response_format", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req openAIRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			if req.ResponseFormat == nil || req.ResponseFormat.Type != "json_object" {
+				t.Errorf("response_format = %+v, want json_object", req.ResponseFormat)
+			}
+			resp := openAIResponse{
+				Choices: []openAIChoice{{Message: openAIMessage{Role: "assistant", Content: "{}"}}},
+			}
+			_ = json.NewEncoder(w).Encode(resp)
+		}))
+		defer server.Close()
+
+		c := &Client{provider: ProviderGroq, host: server.URL, apiPath: consts.LLMPathGroq, timeout: 10 * time.Second}
+		if _, err := c.Generate(context.Background(), "test-model", "test prompt", GenerateOptions{JSON: true}); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+	})
+
+	t.Run("ollama format", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req ollamaRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			if req.Format != "json" {
+				t.Errorf("format = %q, want json", req.Format)
+			}
+			_ = json.NewEncoder(w).Encode(ollamaResponse{Response: "{}"})
+		}))
+		defer server.Close()
+
+		c := &Client{provider: ProviderOllama, host: server.URL, apiPath: consts.LLMPathOllama, timeout: 10 * time.Second}
+		if _, err := c.Generate(context.Background(), "test-model", "test prompt", GenerateOptions{JSON: true}); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "response_format is not supported", http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		c := &Client{provider: ProviderGroq, host: server.URL, apiPath: consts.LLMPathGroq, timeout: 10 * time.Second}
+		_, err := c.Generate(context.Background(), "test-model", "test prompt", GenerateOptions{JSON: true})
+		if !IsUnsupportedError(err) {
+			t.Errorf("IsUnsupportedError(%v) = false, want true", err)
+		}
+	})
+}
+
+func TestIsUnsupportedError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"bad request", &StatusError{StatusCode: 400}, true},
+		{"not found", &StatusError{StatusCode: 404}, true},
+		{"unprocessable", &StatusError{StatusCode: 422}, true},
+		{"not implemented", &StatusError{StatusCode: 501}, true},
+		{"unauthorized", &StatusError{StatusCode: 401}, false},
+		{"server error", &StatusError{StatusCode: 500}, false},
+		{"wrapped", fmt.Errorf("after retries: %w", &StatusError{StatusCode: 400}), true},
+		{"other", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsUnsupportedError(tt.err); got != tt.want {
+				t.Errorf("IsUnsupportedError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_History(t *testing.T) {
+	history := []Turn{
+		{Role: RoleUser, Content: "generate"},
+		{Role: RoleAssistant, Content: "feat(auth): add login"},
+	}
+
+	t.Run("openai messages", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req openAIRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			want := []openAIMessage{
+				{Role: "system", Content: "system prompt"},
+				{Role: RoleUser, Content: "generate"},
+				{Role: RoleAssistant, Content: "feat(auth): add login"},
+				{Role: RoleUser, Content: "make it a fix"},
+			}
+			if len(req.Messages) != len(want) {
+				t.Fatalf("messages = %+v, want %+v", req.Messages, want)
+			}
+			for i := range want {
+				if req.Messages[i] != want[i] {
+					t.Errorf("messages[%d] = %+v, want %+v", i, req.Messages[i], want[i])
+				}
+			}
+			resp := openAIResponse{
+				Choices: []openAIChoice{{Message: openAIMessage{Role: "assistant", Content: "fix(auth): add login"}}},
+			}
+			_ = json.NewEncoder(w).Encode(resp)
+		}))
+		defer server.Close()
+
+		c := &Client{provider: ProviderGroq, host: server.URL, apiPath: consts.LLMPathGroq, timeout: 10 * time.Second}
+		opt := GenerateOptions{System: "system prompt", History: history}
+		if _, err := c.Generate(context.Background(), "test-model", "make it a fix", opt); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+	})
+
+	t.Run("ollama transcript", func(t *testing.T) {
+		got := flattenHistory(history, "make it a fix")
+		want := "User:\ngenerate\n\nAssistant:\nfeat(auth): add login\n\nUser:\nmake it a fix\n\nAssistant:\n"
+		if got != want {
+			t.Errorf("flattenHistory() = %q, want %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,217 @@
+package llm
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/mritd/gitflow-toolkit/v3/config"
+	"github.com/mritd/gitflow-toolkit/v3/consts"
+)
+
+// examples holds the built-in few-shot commit messages, one file per language code.
+//
+//go:embed examples/*.txt
+var examples embed.FS
+
+// langCode matches language codes such as "en", "ja" or "pt-br".
+var langCode = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+// languageNames maps common language codes to the names used in prompts.
+var languageNames = map[string]string{
+	"en":    "English",
+	"zh":    "Simplified Chinese",
+	"zh-cn": "Simplified Chinese",
+	"zh-tw": "Traditional Chinese",
+	"ja":    "Japanese",
+	"ko":    "Korean",
+	"de":    "German",
+	"fr":    "French",
+	"es":    "Spanish",
+	"pt":    "Portuguese",
+	"pt-br": "Brazilian Portuguese",
+	"it":    "Italian",
+	"nl":    "Dutch",
+	"pl":    "Polish",
+	"ru":    "Russian",
+	"uk":    "Ukrainian",
+	"tr":    "Turkish",
+	"sv":    "Swedish",
+	"vi":    "Vietnamese",
+	"id":    "Indonesian",
+	"hi":    "Hindi",
+	"ar":    "Arabic",
+}
+
+// Language is the commit message output language: a single language, or a bilingual
+// pair with the subject in Primary followed by its Secondary translation in parentheses
+// and the body in Secondary.
+type Language struct {
+	Primary   string // language code of the subject
+	Secondary string // language code of the translation and body, empty for a single language
+}
+
+// DefaultLanguage is used when llm-output-lang is unset or invalid.
+var DefaultLanguage = Language{Primary: consts.LLMDefaultLang}
+
+// ParseLanguage parses a language code ("ja"), a bilingual pair ("en+ja")
+// or the "bilingual" alias for en+zh. Returns false if the value is invalid.
+func ParseLanguage(s string) (Language, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == consts.LLMLangBilingual {
+		return Language{Primary: consts.LLMLangEN, Secondary: consts.LLMLangZH}, true
+	}
+
+	primary, secondary, pair := strings.Cut(s, consts.LLMLangPairSep)
+	primary, secondary = strings.TrimSpace(primary), strings.TrimSpace(secondary)
+	if !langCode.MatchString(primary) {
+		return Language{}, false
+	}
+	if !pair {
+		return Language{Primary: primary}, true
+	}
+	if !langCode.MatchString(secondary) || secondary == primary {
+		return Language{}, false
+	}
+	return Language{Primary: primary, Secondary: secondary}, true
+}
+
+// String returns the language code, e.g. "en" or "en+ja".
+func (l Language) String() string {
+	l = l.orDefault()
+	if l.Bilingual() {
+		return l.Primary + consts.LLMLangPairSep + l.Secondary
+	}
+	return l.Primary
+}
+
+// Bilingual reports whether the language is a bilingual pair.
+func (l Language) Bilingual() bool {
+	return l.Secondary != ""
+}
+
+// CommitPrompt returns the default commit system prompt for the language.
+func (l Language) CommitPrompt() string {
+	l = l.orDefault()
+	if l.Bilingual() {
+		return fmt.Sprintf(consts.LLMCommitPromptBilingual, LanguageName(l.Primary), LanguageName(l.Secondary))
+	}
+
+	var subjectRules string
+	if l.Primary == consts.LLMLangEN {
+		subjectRules = ", lowercase"
+	}
+	return fmt.Sprintf(consts.LLMCommitPrompt, LanguageName(l.Primary), subjectRules)
+}
+
+// CommitJSONPrompt returns the default commit system prompt for the language when
+// the message is requested as a JSON object; types is the comma-separated list of
+// commit types and maxSubject the maximum subject length.
+func (l Language) CommitJSONPrompt(types string, maxSubject int) string {
+	l = l.orDefault()
+	if l.Bilingual() {
+		return fmt.Sprintf(consts.LLMCommitJSONPromptBilingual, LanguageName(l.Primary), LanguageName(l.Secondary), types, maxSubject)
+	}
+
+	var subjectRules string
+	if l.Primary == consts.LLMLangEN {
+		subjectRules = ", lowercase"
+	}
+	return fmt.Sprintf(consts.LLMCommitJSONPrompt, LanguageName(l.Primary), subjectRules, types, maxSubject)
+}
+
+// CommitPromptKey returns the gitconfig key holding a custom commit prompt for the language.
+func (l Language) CommitPromptKey() string {
+	l = l.orDefault()
+	if l.Bilingual() {
+		return config.GitConfigLLMCommitPromptPrefix + l.Primary + "-" + l.Secondary
+	}
+	return config.GitConfigLLMCommitPromptPrefix + l.Primary
+}
+
+// Example returns the few-shot commit message for the language, looked up in the
+// llm-examples-dir directory first and then in the built-in examples.
+// The second result is false if no example exists in the language and the
+// English example is returned instead.
+func (l Language) Example() (string, bool) {
+	return l.example(config.GetString(config.GitConfigLLMExamplesDir, ""))
+}
+
+func (l Language) example(dir string) (string, bool) {
+	l = l.orDefault()
+	if msg, ok := loadExample(dir, l.String()); ok {
+		return msg, true
+	}
+
+	if l.Bilingual() {
+		// Compose the pair from the single language examples
+		primary, ok1 := loadExample(dir, l.Primary)
+		secondary, ok2 := loadExample(dir, l.Secondary)
+		if ok1 && ok2 {
+			return composeBilingual(primary, secondary), true
+		}
+	}
+
+	msg, _ := loadExample(dir, consts.LLMLangEN)
+	return msg, false
+}
+
+func (l Language) orDefault() Language {
+	if l.Primary == "" {
+		return DefaultLanguage
+	}
+	return l
+}
+
+// LanguageName returns the name of a language code for use in prompts.
+func LanguageName(code string) string {
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	// Fall back to the base language, e.g. "de-ch" -> German (de-ch)
+	if base, _, ok := strings.Cut(code, "-"); ok {
+		if name, ok := languageNames[base]; ok {
+			return fmt.Sprintf("%s (%s)", name, code)
+		}
+	}
+	return fmt.Sprintf("the language with code %q", code)
+}
+
+// loadExample reads the example for code ("ja", "en+ja") from dir, or from the
+// built-in examples. Region codes fall back to the base language ("pt-br" -> "pt").
+func loadExample(dir, code string) (string, bool) {
+	codes := []string{code}
+	if !strings.Contains(code, consts.LLMLangPairSep) {
+		if base, _, ok := strings.Cut(code, "-"); ok {
+			codes = append(codes, base)
+		}
+	}
+
+	for _, c := range codes {
+		if dir != "" {
+			if data, err := os.ReadFile(filepath.Join(dir, c+".txt")); err == nil {
+				return strings.TrimSpace(string(data)), true
+			}
+		}
+		if data, err := examples.ReadFile("examples/" + c + ".txt"); err == nil {
+			return strings.TrimSpace(string(data)), true
+		}
+	}
+	return "", false
+}
+
+// composeBilingual builds a bilingual example from two single language examples:
+// the primary header with the secondary subject in parentheses, and the secondary body.
+func composeBilingual(primary, secondary string) string {
+	pHeader, _, _ := strings.Cut(primary, "\n")
+	sHeader, sBody, _ := strings.Cut(secondary, "\n")
+
+	subject := sHeader
+	if i := strings.Index(sHeader, ": "); i >= 0 {
+		subject = sHeader[i+2:]
+	}
+	return pHeader + " (" + subject + ")\n" + strings.TrimRight(sBody, "\n")
+}
@@ -0,0 +1,154 @@
+package llm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   Language
+		wantOK bool
+	}{
+		{"english", "en", Language{Primary: "en"}, true},
+		{"japanese", "ja", Language{Primary: "ja"}, true},
+		{"region", "pt-BR", Language{Primary: "pt-br"}, true},
+		{"pair", "en+ja", Language{Primary: "en", Secondary: "ja"}, true},
+		{"pair with spaces", " de + en ", Language{Primary: "de", Secondary: "en"}, true},
+		{"bilingual alias", "bilingual", Language{Primary: "en", Secondary: "zh"}, true},
+		{"empty", "", Language{}, false},
+		{"name instead of code", "japanese", Language{}, false},
+		{"same pair", "en+en", Language{}, false},
+		{"incomplete pair", "en+", Language{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseLanguage(tt.input)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("ParseLanguage(%q) = %+v, %v, want %+v, %v", tt.input, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLanguageName(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"ja", "Japanese"},
+		{"pt-br", "Brazilian Portuguese"},
+		{"de-ch", "German (de-ch)"},
+		{"xx", `the language with code "xx"`},
+	}
+
+	for _, tt := range tests {
+		if got := LanguageName(tt.code); got != tt.want {
+			t.Errorf("LanguageName(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestLanguageCommitPrompt(t *testing.T) {
+	en := Language{Primary: "en"}.CommitPrompt()
+	if !strings.Contains(en, "written in English, imperative mood, lowercase") {
+		t.Errorf("English prompt should require lowercase subject, got:\n%s", en)
+	}
+
+	ja := Language{Primary: "ja"}.CommitPrompt()
+	if !strings.Contains(ja, "written in Japanese") || strings.Contains(ja, "lowercase") {
+		t.Errorf("Japanese prompt = \n%s", ja)
+	}
+
+	pair := Language{Primary: "en", Secondary: "ja"}.CommitPrompt()
+	if !strings.Contains(pair, "<subject in English> (<subject in Japanese>)") {
+		t.Errorf("bilingual prompt should describe the subject pair, got:\n%s", pair)
+	}
+
+	if got := (Language{}).CommitPrompt(); got != en {
+		t.Error("zero Language should use the English prompt")
+	}
+}
+
+func TestLanguageCommitJSONPrompt(t *testing.T) {
+	en := Language{Primary: "en"}.CommitJSONPrompt("feat, fix", 72)
+	for _, want := range []string{"one of: feat, fix", "lowercase, no period, max 72 chars", "OUTPUT ONLY THE JSON OBJECT"} {
+		if !strings.Contains(en, want) {
+			t.Errorf("JSON prompt should contain %q, got:\n%s", want, en)
+		}
+	}
+	for _, conflict := range []string{"<type>(<scope>): <subject>", "max 50 chars", "OUTPUT ONLY THE COMMIT MESSAGE"} {
+		if strings.Contains(en, conflict) {
+			t.Errorf("JSON prompt should not contain the text format %q, got:\n%s", conflict, en)
+		}
+	}
+
+	pair := Language{Primary: "en", Secondary: "ja"}.CommitJSONPrompt("feat, fix", 72)
+	if !strings.Contains(pair, `"subject": "<subject in English> (<subject in Japanese>)"`) {
+		t.Errorf("bilingual JSON prompt should describe the subject pair, got:\n%s", pair)
+	}
+}
+
+func TestLanguageCommitPromptKey(t *testing.T) {
+	if got := (Language{Primary: "ja"}).CommitPromptKey(); got != "llm-commit-prompt-ja" {
+		t.Errorf("CommitPromptKey() = %q", got)
+	}
+	if got := (Language{Primary: "en", Secondary: "ja"}).CommitPromptKey(); got != "llm-commit-prompt-en-ja" {
+		t.Errorf("CommitPromptKey() = %q", got)
+	}
+}
+
+func TestLanguageExample(t *testing.T) {
+	t.Run("built-in", func(t *testing.T) {
+		got, native := Language{Primary: "ja"}.example("")
+		if !native || !strings.HasPrefix(got, "feat(api): ユーザー認証") {
+			t.Errorf("example() = %q, %v", got, native)
+		}
+	})
+
+	t.Run("region falls back to base", func(t *testing.T) {
+		got, native := Language{Primary: "pt-br"}.example("")
+		if !native || !strings.Contains(got, "adicionar autenticação") {
+			t.Errorf("example() = %q, %v", got, native)
+		}
+	})
+
+	t.Run("composed pair", func(t *testing.T) {
+		got, native := Language{Primary: "en", Secondary: "zh"}.example("")
+		want := "feat(api): add authentication and user profile (添加用户认证和资料功能)\n\n- 实现 JWT token 验证"
+		if !native || !strings.HasPrefix(got, want) {
+			t.Errorf("example() = %q, want prefix %q", got, want)
+		}
+	})
+
+	t.Run("unknown language uses english", func(t *testing.T) {
+		got, native := Language{Primary: "sv"}.example("")
+		if native || !strings.HasPrefix(got, "feat(api): add authentication") {
+			t.Errorf("example() = %q, %v", got, native)
+		}
+	})
+
+	t.Run("examples dir overrides", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "sv.txt"), []byte("feat(api): lägg till inloggning\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "en+sv.txt"), []byte("feat(api): add login (lägg till inloggning)\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		got, native := Language{Primary: "sv"}.example(dir)
+		if !native || got != "feat(api): lägg till inloggning" {
+			t.Errorf("example() = %q, %v", got, native)
+		}
+		got, native = Language{Primary: "en", Secondary: "sv"}.example(dir)
+		if !native || got != "feat(api): add login (lägg till inloggning)" {
+			t.Errorf("pair example() = %q, %v", got, native)
+		}
+	})
+}
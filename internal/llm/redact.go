@@ -0,0 +1,147 @@
+package llm
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Redaction kinds reported for built-in detectors.
+const (
+	RedactAWSAccessKey = "AWS access key"
+	RedactAWSSecretKey = "AWS secret key"
+	RedactGitHubToken  = "GitHub token"
+	RedactPrivateKey   = "private key"
+	RedactSecretValue  = "secret assignment"
+	RedactEnvValue     = ".env value"
+	RedactHighEntropy  = "high-entropy string"
+	RedactCustom       = "custom pattern"
+)
+
+// redactRule masks matches of a regular expression.
+// If the expression has a capture group, only the first group is masked.
+type redactRule struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+// builtinRedactRules are the secret detectors applied to every diff.
+var builtinRedactRules = []redactRule{
+	{RedactPrivateKey, regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----(?:.*?-----END [A-Z ]*PRIVATE KEY-----|.*)`)},
+	{RedactAWSAccessKey, regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{RedactAWSSecretKey, regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})`)},
+	{RedactGitHubToken, regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{RedactSecretValue, regexp.MustCompile(`(?i)[A-Za-z0-9_.-]*(?:api[_-]?key|secret|token|passw(?:or)?d)[A-Za-z0-9_.-]*["']?\s*[:=]\s*["']([^"'\s]{8,})["']`)},
+}
+
+// envAssignment matches KEY=VALUE lines in .env files (diff line prefix included).
+var envAssignment = regexp.MustCompile(`(?m)^([+\- ]\s*(?:export\s+)?[A-Za-z_][A-Za-z0-9_]*\s*=\s*)(\S.*)$`)
+
+// entropyCandidate matches tokens long enough to be credentials.
+var entropyCandidate = regexp.MustCompile(`[A-Za-z0-9+/=_\-]{24,}`)
+
+// Redactor masks secrets in diffs before they are sent to a remote LLM.
+type Redactor struct {
+	builtin bool
+	custom  []redactRule
+}
+
+// NewRedactor creates a redactor with built-in detectors (if enabled) and user regexes.
+func NewRedactor(builtin bool, patterns []string) (*Redactor, error) {
+	r := &Redactor{builtin: builtin}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", p, err)
+		}
+		r.custom = append(r.custom, redactRule{kind: RedactCustom, re: re})
+	}
+	return r, nil
+}
+
+// Redact masks secrets in the diff of the given file.
+// Returns the redacted text and the kind of every masked secret.
+func (r *Redactor) Redact(filePath, text string) (string, []string) {
+	var found []string
+
+	apply := func(rule redactRule) {
+		text = rule.re.ReplaceAllStringFunc(text, func(match string) string {
+			found = append(found, rule.kind)
+			marker := redactMarker(rule.kind)
+			sub := rule.re.FindStringSubmatchIndex(match)
+			if len(sub) >= 4 && sub[2] >= 0 {
+				return match[:sub[2]] + marker + match[sub[3]:]
+			}
+			return marker
+		})
+	}
+
+	if r.builtin {
+		for _, rule := range builtinRedactRules {
+			apply(rule)
+		}
+
+		if isEnvFile(filePath) {
+			text = envAssignment.ReplaceAllStringFunc(text, func(match string) string {
+				found = append(found, RedactEnvValue)
+				sub := envAssignment.FindStringSubmatch(match)
+				return sub[1] + redactMarker(RedactEnvValue)
+			})
+		}
+
+		text = entropyCandidate.ReplaceAllStringFunc(text, func(match string) string {
+			if !isHighEntropy(match) {
+				return match
+			}
+			found = append(found, RedactHighEntropy)
+			return redactMarker(RedactHighEntropy)
+		})
+	}
+
+	for _, rule := range r.custom {
+		apply(rule)
+	}
+
+	return text, found
+}
+
+// redactMarker returns the placeholder that replaces a secret.
+func redactMarker(kind string) string {
+	return "[REDACTED " + kind + "]"
+}
+
+// isEnvFile reports whether the path is a dotenv file (.env, .env.local, ...).
+func isEnvFile(filePath string) bool {
+	base := path.Base(filePath)
+	return base == ".env" || strings.HasPrefix(base, ".env.")
+}
+
+// isHighEntropy reports whether a token looks like a random credential:
+// it must mix letters and digits and have Shannon entropy above 4.5 bits per char.
+// Plain hex strings (commit hashes, checksums) stay below the threshold.
+func isHighEntropy(s string) bool {
+	var hasLetter, hasDigit bool
+	counts := make(map[rune]int)
+	for _, c := range s {
+		counts[c]++
+		switch {
+		case c >= '0' && c <= '9':
+			hasDigit = true
+		case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			hasLetter = true
+		}
+	}
+	if !hasLetter || !hasDigit {
+		return false
+	}
+
+	var entropy float64
+	n := float64(len(s))
+	for _, count := range counts {
+		p := float64(count) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy > 4.5
+}
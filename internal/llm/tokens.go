@@ -0,0 +1,19 @@
+package llm
+
+import "unicode/utf8"
+
+// EstimateTokens returns a rough token count for the given text.
+// Uses the common heuristic of ~4 bytes per token for code and English,
+// and counts each non-ASCII rune as one token since CJK text tokenizes densely.
+func EstimateTokens(s string) int {
+	ascii := 0
+	tokens := 0
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			tokens++
+		}
+	}
+	return tokens + (ascii+3)/4
+}
@@ -0,0 +1,25 @@
+package llm
+
+import "testing"
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{"empty", "", 0},
+		{"short ascii", "abc", 1},
+		{"ascii", "package main\n", 4},
+		{"cjk", "添加功能", 4},
+		{"mixed", "add 功能", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EstimateTokens(tt.input); got != tt.want {
+				t.Errorf("EstimateTokens(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
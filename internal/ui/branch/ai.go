@@ -0,0 +1,205 @@
+package branch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/mritd/gitflow-toolkit/v3/consts"
+	"github.com/mritd/gitflow-toolkit/v3/internal/git"
+	"github.com/mritd/gitflow-toolkit/v3/internal/llm"
+	"github.com/mritd/gitflow-toolkit/v3/internal/ui/common"
+)
+
+// maxSlugLen is the maximum length of a suggested branch slug.
+const maxSlugLen = 40
+
+// NewAIModel creates a branch model that asks the LLM for a branch type and name
+// from a task description, then lets the user confirm or adjust it before creating it.
+func NewAIModel(branchType, description string, client *llm.Client) Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(common.ColorPrimary)
+
+	ti := textinput.New()
+	ti.Prompt = ""
+	ti.CharLimit = maxSlugLen
+
+	return Model{
+		branchType:  branchType,
+		description: description,
+		client:      client,
+		input:       ti,
+		state:       StateSuggesting,
+		spinner:     s,
+	}
+}
+
+// branchSuggestedMsg is sent when the LLM suggestion is available.
+type branchSuggestedMsg struct {
+	suggestion string
+	err        error
+}
+
+// suggestBranch asks the LLM for a branch name.
+func (m Model) suggestBranch() tea.Cmd {
+	return func() tea.Msg {
+		suggestion, err := generateSuggestion(m.client, m.description)
+		return branchSuggestedMsg{suggestion: suggestion, err: err}
+	}
+}
+
+// generateSuggestion returns the raw LLM suggestion for a task description.
+func generateSuggestion(client *llm.Client, description string) (string, error) {
+	opt := llm.GenerateOptions{System: consts.LLMBranchPrompt}
+	return client.Generate(context.Background(), client.GetModel(), description, opt)
+}
+
+// SuggestBranch asks the LLM for a branch type and name without any TUI.
+// The type falls back to defaultType if the suggestion has none.
+func SuggestBranch(client *llm.Client, defaultType, description string) (branchType, slug string, err error) {
+	suggestion, err := generateSuggestion(client, description)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to suggest branch name: %w", err)
+	}
+	branchType, slug = resolveSuggestion(suggestion, defaultType, description)
+	if slug == "" {
+		return "", "", errors.New("no branch name could be derived from the description")
+	}
+	return branchType, slug, nil
+}
+
+// resolveSuggestion parses the suggestion, using the slugified description if it has no name.
+func resolveSuggestion(suggestion, defaultType, description string) (branchType, slug string) {
+	branchType, slug = parseBranchSuggestion(suggestion, defaultType)
+	if slug == "" {
+		slug = slugify(description)
+	}
+	return branchType, slug
+}
+
+// updateConfirm handles keys while the suggested branch is shown for confirmation.
+func (m Model) updateConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "ctrl+c", "esc":
+			m.state = StateCancelled
+			return m, tea.Quit
+		case "tab", "down":
+			m.branchType = cycleType(m.branchType, 1)
+			return m, nil
+		case "shift+tab", "up":
+			m.branchType = cycleType(m.branchType, -1)
+			return m, nil
+		case "enter":
+			slug := slugify(m.input.Value())
+			if slug == "" {
+				return m, nil
+			}
+			m.branchName = slug
+			m.fullName = m.branchType + "/" + slug
+			m.state = StateCreating
+			return m, tea.Batch(m.spinner.Tick, m.createBranch())
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// viewConfirm renders the suggested branch with the editable slug.
+func (m Model) viewConfirm() string {
+	titleLayout := lipgloss.NewStyle().Padding(1, 0, 1, 2)
+	titleStyle := lipgloss.NewStyle().
+		Foreground(common.ColorTitleFg).
+		Background(common.ColorTitleBg).
+		Bold(true).
+		Padding(0, 1)
+	contentLayout := lipgloss.NewStyle().PaddingLeft(2)
+	mutedStyle := lipgloss.NewStyle().Foreground(common.ColorMuted)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(common.ColorMuted).
+		PaddingLeft(2).
+		PaddingTop(1)
+
+	var sb strings.Builder
+	sb.WriteString(titleLayout.Render(titleStyle.Render("Suggested Branch")))
+	sb.WriteString("\n")
+	sb.WriteString(contentLayout.Render(mutedStyle.Render("Task: " + m.description)))
+	sb.WriteString("\n\n")
+	sb.WriteString(contentLayout.Render(common.StyleCommitType.Render(m.branchType+"/") + m.input.View()))
+	sb.WriteString("\n")
+	sb.WriteString(helpStyle.Render("enter create • tab/↑/↓ change type • type to edit name • esc cancel"))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// parseBranchSuggestion extracts the branch type and slug from the model output.
+// The type falls back to defaultType if the suggestion has none or an unknown one.
+func parseBranchSuggestion(raw, defaultType string) (branchType, slug string) {
+	branchType = defaultType
+
+	var line string
+	for _, l := range strings.Split(raw, "\n") {
+		l = strings.Trim(strings.TrimSpace(l), "`*\"'")
+		if l != "" && !strings.HasPrefix(l, "```") {
+			line = l
+			break
+		}
+	}
+	line = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(line, "Output:"), "output:"))
+
+	if i := strings.Index(line, "/"); i > 0 {
+		if t := git.ParseBranchType(line[:i]); t != "" {
+			branchType = t
+		}
+		line = line[i+1:]
+	}
+
+	return branchType, slugify(line)
+}
+
+// slugify converts text into a lowercase, hyphen-separated branch name.
+func slugify(s string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+			dash = false
+		} else if !dash && sb.Len() > 0 {
+			sb.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := strings.TrimRight(sb.String(), "-")
+	if len(slug) > maxSlugLen {
+		slug = slug[:maxSlugLen]
+		// Cut at the last word boundary
+		if i := strings.LastIndex(slug, "-"); i > 0 {
+			slug = slug[:i]
+		}
+	}
+	return slug
+}
+
+// cycleType returns the commit type delta positions away from t.
+func cycleType(t string, delta int) string {
+	n := len(consts.CommitTypes)
+	idx := 0
+	for i, ct := range consts.CommitTypes {
+		if ct.Name == t {
+			idx = i
+			break
+		}
+	}
+	return consts.CommitTypes[((idx+delta)%n+n)%n].Name
+}
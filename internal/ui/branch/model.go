@@ -6,10 +6,12 @@ import (
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/mritd/gitflow-toolkit/v3/internal/git"
+	"github.com/mritd/gitflow-toolkit/v3/internal/llm"
 	"github.com/mritd/gitflow-toolkit/v3/internal/ui/common"
 )
 
@@ -20,6 +22,9 @@ const (
 	StateCreating State = iota
 	StateSuccess
 	StateFailed
+	StateSuggesting // waiting for the LLM branch suggestion
+	StateConfirm    // suggested branch shown for confirmation
+	StateCancelled
 )
 
 // Model is the branch creation UI model.
@@ -31,6 +36,11 @@ type Model struct {
 	spinner    spinner.Model
 	err        error
 	result     string
+
+	// AI branch naming
+	description string          // task description sent to the LLM
+	client      *llm.Client     // nil unless created with NewAIModel
+	input       textinput.Model // editable branch name
 }
 
 // NewModel creates a new branch model.
@@ -50,6 +60,9 @@ func NewModel(branchType, branchName string) Model {
 
 // Init initializes the model.
 func (m Model) Init() tea.Cmd {
+	if m.state == StateSuggesting {
+		return tea.Batch(m.spinner.Tick, m.suggestBranch())
+	}
 	return tea.Batch(
 		m.spinner.Tick,
 		m.createBranch(),
@@ -72,15 +85,35 @@ func (m Model) createBranch() tea.Cmd {
 
 // Update handles messages.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.state == StateConfirm {
+		return m.updateConfirm(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q", "esc", "enter":
+			if m.state == StateSuggesting {
+				m.state = StateCancelled
+			}
 			return m, tea.Quit
 		}
 
+	case branchSuggestedMsg:
+		if msg.err != nil {
+			m.state = StateFailed
+			m.err = fmt.Errorf("failed to suggest branch name: %w", msg.err)
+			return m, tea.Quit
+		}
+		branchType, slug := resolveSuggestion(msg.suggestion, m.branchType, m.description)
+		m.branchType = branchType
+		m.input.SetValue(slug)
+		m.input.CursorEnd()
+		m.state = StateConfirm
+		return m, m.input.Focus()
+
 	case spinner.TickMsg:
-		if m.state == StateCreating {
+		if m.state == StateCreating || m.state == StateSuggesting {
 			var cmd tea.Cmd
 			m.spinner, cmd = m.spinner.Update(msg)
 			return m, cmd
@@ -108,6 +141,16 @@ func (m Model) View() string {
 	branchStyle := common.StyleCommitType
 
 	switch m.state {
+	case StateSuggesting:
+		return m.spinner.View() + " Suggesting branch name...\n"
+
+	case StateConfirm:
+		return m.viewConfirm()
+
+	case StateCancelled:
+		r := common.Warning("Branch creation cancelled", "Operation was cancelled by user.")
+		return common.RenderResult(r)
+
 	case StateCreating:
 		return m.spinner.View() + " Creating branch " + branchStyle.Render(m.fullName) + "...\n"
 
@@ -220,3 +220,108 @@ func TestModel_Init(t *testing.T) {
 		t.Error("Init() should return a command")
 	}
 }
+
+func TestParseBranchSuggestion(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		wantType string
+		wantSlug string
+	}{
+		{"type and slug", "fix/password-reset-plus-email", "fix", "password-reset-plus-email"},
+		{"alias type", "feature/add-login", "feat", "add-login"},
+		{"unknown type", "wip/add-login", "feat", "add-login"},
+		{"no type", "add-login", "feat", "add-login"},
+		{"decorated", "```\n`Output: docs/Update README`\n```", "docs", "update-readme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotType, gotSlug := parseBranchSuggestion(tt.raw, "feat")
+			if gotType != tt.wantType || gotSlug != tt.wantSlug {
+				t.Errorf("parseBranchSuggestion() = %q, %q, want %q, %q", gotType, gotSlug, tt.wantType, tt.wantSlug)
+			}
+		})
+	}
+}
+
+func TestResolveSuggestion(t *testing.T) {
+	gotType, gotSlug := resolveSuggestion("fix/", "feat", "Reset password emails")
+	if gotType != "fix" || gotSlug != "reset-password-emails" {
+		t.Errorf("resolveSuggestion() = %q, %q, want fix, reset-password-emails", gotType, gotSlug)
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Users can't reset password", "users-can-t-reset-password"},
+		{"  --add__login--  ", "add-login"},
+		{"修复 login", "login"},
+		{strings.Repeat("word-", 20), "word-word-word-word-word-word-word-word"},
+	}
+
+	for _, tt := range tests {
+		if got := slugify(tt.in); got != tt.want {
+			t.Errorf("slugify(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCycleType(t *testing.T) {
+	if got := cycleType("feat", 1); got != "fix" {
+		t.Errorf("cycleType(feat, 1) = %q, want fix", got)
+	}
+	if got := cycleType("feat", -1); got != "hotfix" {
+		t.Errorf("cycleType(feat, -1) = %q, want hotfix", got)
+	}
+}
+
+func TestAIModel_Flow(t *testing.T) {
+	m := NewAIModel("feat", "users can't reset password", nil)
+	if m.state != StateSuggesting {
+		t.Fatalf("state = %v, want StateSuggesting", m.state)
+	}
+
+	update := func(m Model, msg tea.Msg) (Model, tea.Cmd) {
+		updated, cmd := m.Update(msg)
+		return updated.(Model), cmd
+	}
+
+	m, _ = update(m, branchSuggestedMsg{suggestion: "fix/password-reset"})
+	if m.state != StateConfirm || m.branchType != "fix" || m.input.Value() != "password-reset" {
+		t.Fatalf("after suggestion: state = %v, type = %q, slug = %q", m.state, m.branchType, m.input.Value())
+	}
+
+	m, _ = update(m, tea.KeyMsg{Type: tea.KeyTab})
+	if m.branchType != "docs" {
+		t.Errorf("after tab: type = %q, want docs", m.branchType)
+	}
+
+	m, cmd := update(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.state != StateCreating || m.fullName != "docs/password-reset" || cmd == nil {
+		t.Errorf("after enter: state = %v, fullName = %q", m.state, m.fullName)
+	}
+}
+
+func TestAIModel_Cancel(t *testing.T) {
+	m := NewAIModel("feat", "task", nil)
+	updated, _ := m.Update(branchSuggestedMsg{suggestion: "feat/task"})
+	updated, cmd := updated.(Model).Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	if updated.(Model).state != StateCancelled || cmd == nil {
+		t.Errorf("esc: state = %v, want StateCancelled and quit", updated.(Model).state)
+	}
+}
+
+func TestAIModel_SuggestError(t *testing.T) {
+	m := NewAIModel("feat", "task", nil)
+	updated, _ := m.Update(branchSuggestedMsg{err: errors.New("connection refused")})
+
+	model := updated.(Model)
+	if model.state != StateFailed || model.Error() == nil {
+		t.Errorf("state = %v, err = %v, want StateFailed with error", model.state, model.Error())
+	}
+}
@@ -2,39 +2,68 @@ package commit
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/mritd/gitflow-toolkit/v3/config"
 	"github.com/mritd/gitflow-toolkit/v3/consts"
 	"github.com/mritd/gitflow-toolkit/v3/internal/git"
 	"github.com/mritd/gitflow-toolkit/v3/internal/llm"
 	"github.com/mritd/gitflow-toolkit/v3/internal/ui/common"
 )
 
+// AI generation strategies.
+const (
+	strategySinglePass = "single-pass" // whole diff in one request
+	strategyPerFile    = "per-file"    // per-file summaries, then final message
+)
+
 // aiResult represents the result of AI generation.
 type aiResult struct {
-	Message   string
+	Messages  []string // candidate messages, at least one on success
+	Strategy  string
 	Cancelled bool
 	Err       error
+
+	state *aiModel // finished model, reused to regenerate only the final message
 }
 
 // aiModel is the bubbletea model for AI generation progress.
 type aiModel struct {
 	files          []git.FileDiff
+	ignored        []string // paths excluded by llm-ignore patterns, listed in the final prompt only
+	plans          []filePlan
+	redactions     [][]string // per-file kinds of secrets masked before analysis
+	history        branchHistory
 	summaries      []string
-	fileStatus     []int // 0=pending, 1=running, 2=done, -1=error
+	fileStatus     []int  // 0=pending, 1=running, 2=done, 3=skipped, -1=error
+	fileCached     []bool // summary served from the on-disk cache
+	cache          *llm.Cache
 	completedCount int
+	skippedCount   int
+	cachedCount    int
 	runningCount   int
 	concurrency    int
-	finalMsg       string
+	candidates     int      // number of final messages to generate
+	finalMsgs      []string // generated candidate messages
 	spinner        spinner.Model
 	progressPos    int
-	phase          string // "analyzing" or "generating"
+	phase          string     // "analyzing" or "generating"
+	strategy       string     // strategySinglePass or strategyPerFile
+	structured     bool       // request JSON output for the final message
+	pr             *prContext // non-nil when generating a PR description instead of a commit message
+	review         bool       // review files for problems instead of summarizing them
+	refineHistory  []llm.Turn // previous refinements (assistant message, user instruction)
+	refineCurrent  string     // message being refined
+	refineRequest  string     // refinement instruction
 	done           bool
 	cancelled      bool
 	err            error
@@ -47,48 +76,123 @@ type aiModel struct {
 type aiFileAnalyzedMsg struct {
 	idx     int
 	summary string
+	cached  bool // every chunk was served from the cache
 	err     error
 }
 
 type aiFinalGeneratedMsg struct {
-	message string
-	err     error
+	messages []string
+	err      error
 }
 
 type aiTickMsg struct{}
 
-func newAIModel(files []git.FileDiff, client *llm.Client) aiModel {
+// aiInput holds the prepared inputs for AI generation.
+type aiInput struct {
+	files      []git.FileDiff
+	ignored    []string
+	redactions [][]string
+	history    branchHistory
+	cache      *llm.Cache // nil disables caching of file summaries
+	pr         *prContext // generate a PR description instead of a commit message
+	review     bool       // review files instead of generating a message
+}
+
+// branchHistory describes the current branch, used as context for the final prompt.
+type branchHistory struct {
+	branch     string
+	branchType string   // commit type parsed from the branch name
+	commits    []string // recent commit subjects on the branch
+}
+
+func newAIModel(input aiInput, client *llm.Client) aiModel {
+	files := input.files
+
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(common.ColorPrimary)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	concurrency := llm.GetConcurrency()
+	maxTokens := llm.GetMaxFileTokens()
 
-	// Initialize file status: mark first N files as running (1)
+	// Plan each file: skipped files get a local summary and never reach the LLM
+	plans := make([]filePlan, len(files))
+	summaries := make([]string, len(files))
 	fileStatus := make([]int, len(files))
+	skippedCount := 0
+	for i, file := range files {
+		plans[i] = planFile(file, maxTokens)
+		if plans[i].skipReason != "" {
+			summaries[i] = skippedSummary(file, plans[i].skipReason)
+			fileStatus[i] = 3 // skipped
+			skippedCount++
+		}
+	}
+
+	// Small diffs are sent in a single request instead of per-file analysis.
+	// Reviews always run per file so findings can be grouped by file, and
+	// without any file to send the final prompt lists the ignored paths only.
+	strategy := strategyPerFile
+	if threshold := llm.GetSinglePassTokens(); !input.review && len(files) > 0 && threshold > 0 && analyzableTokens(files, plans) <= threshold {
+		strategy = strategySinglePass
+	}
+
+	// Mark first N analyzable files as running (1), or all of them in single-pass mode
 	runningCount := 0
 	for i := range files {
-		if runningCount >= concurrency {
+		if strategy == strategyPerFile && runningCount >= concurrency {
 			break
 		}
-		fileStatus[i] = 1 // running
-		runningCount++
+		if fileStatus[i] == 0 {
+			fileStatus[i] = 1 // running
+			runningCount++
+		}
+	}
+
+	phase := "analyzing"
+	if strategy == strategySinglePass || skippedCount == len(files) {
+		phase = "generating"
 	}
 
-	return aiModel{
+	m := aiModel{
 		files:          files,
-		summaries:      make([]string, len(files)),
+		ignored:        input.ignored,
+		plans:          plans,
+		redactions:     input.redactions,
+		history:        input.history,
+		summaries:      summaries,
 		fileStatus:     fileStatus,
-		completedCount: 0,
+		fileCached:     make([]bool, len(files)),
+		cache:          input.cache,
+		completedCount: skippedCount,
+		skippedCount:   skippedCount,
 		runningCount:   runningCount,
 		concurrency:    concurrency,
 		spinner:        s,
-		phase:          "analyzing",
+		phase:          phase,
+		strategy:       strategy,
+		structured:     config.GetBool(config.GitConfigLLMStructuredOutput, true),
+		candidates:     llm.GetCandidates(),
+		pr:             input.pr,
+		review:         input.review,
 		client:         client,
 		ctx:            ctx,
 		cancel:         cancel,
 	}
+
+	// Nothing to review if every file is skipped
+	if m.review && m.phase == "generating" {
+		m.done = true
+	}
+
+	// PR descriptions are a single free-form Markdown answer
+	if m.pr != nil {
+		m.structured = false
+		m.candidates = 1
+	}
+
+	return m
 }
 
 func (m aiModel) Init() tea.Cmd {
@@ -96,6 +200,21 @@ func (m aiModel) Init() tea.Cmd {
 	// File status already set in constructor
 	cmds := []tea.Cmd{m.spinner.Tick, m.tickAnimation()}
 
+	// Nothing to review (all files skipped)
+	if m.review && m.done {
+		return tea.Quit
+	}
+
+	// Refine an existing message with a follow-up turn
+	if m.phase == "refining" {
+		return tea.Batch(append(cmds, m.refineMessage())...)
+	}
+
+	// Single-pass mode or nothing to analyze (all files skipped), go straight to the final message
+	if m.phase == "generating" {
+		return tea.Batch(append(cmds, m.generateFinalMessage())...)
+	}
+
 	for i := range m.files {
 		if m.fileStatus[i] == 1 { // running
 			cmds = append(cmds, m.analyzeFile(i))
@@ -130,9 +249,9 @@ func (m aiModel) startNextFile() (int, tea.Cmd) {
 
 func (m aiModel) analyzeFile(idx int) tea.Cmd {
 	file := m.files[idx]
+	chunks := m.plans[idx].chunks
 
 	return func() tea.Msg {
-		prompt := m.buildFilePrompt(file)
 		opt := llm.GenerateOptions{
 			System: consts.LLMDefaultFilePrompt,
 		}
@@ -140,37 +259,231 @@ func (m aiModel) analyzeFile(idx int) tea.Cmd {
 		if customPrompt := m.client.GetFilePrompt(); customPrompt != "" {
 			opt.System = customPrompt
 		}
-		summary, err := m.client.Generate(m.ctx, m.client.GetModel(), prompt, opt)
-		return aiFileAnalyzedMsg{idx: idx, summary: summary, err: err}
+		buildPrompt, sep := m.buildFilePrompt, " "
+		if m.review {
+			opt.System = consts.LLMReviewPrompt
+			buildPrompt, sep = buildReviewPrompt, "\n"
+		}
+
+		// Oversized files are split into hunk groups, analyzed separately and merged
+		parts := make([]string, 0, len(chunks))
+		cached := true
+		for i, chunk := range chunks {
+			part := git.FileDiff{Path: file.Path, Diff: chunk}
+			if len(chunks) > 1 {
+				part.Path = fmt.Sprintf("%s (part %d/%d)", file.Path, i+1, len(chunks))
+			}
+			prompt := buildPrompt(part)
+
+			// Unchanged diffs reuse the summary from a previous run
+			key := llm.CacheKey(m.client.GetModel(), opt.System, prompt)
+			if summary, ok := m.cache.Get(key); ok {
+				parts = append(parts, summary)
+				continue
+			}
+			cached = false
+
+			summary, err := m.client.Generate(m.ctx, m.client.GetModel(), prompt, opt)
+			if err != nil {
+				return aiFileAnalyzedMsg{idx: idx, err: err}
+			}
+			summary = strings.TrimSpace(summary)
+			_ = m.cache.Put(key, summary)
+			parts = append(parts, summary)
+		}
+		return aiFileAnalyzedMsg{idx: idx, summary: strings.Join(parts, sep), cached: cached}
 	}
 }
 
 func (m aiModel) generateFinalMessage() tea.Cmd {
+	if m.pr != nil {
+		return m.generatePRDescription()
+	}
+
 	return func() tea.Msg {
-		prompt := m.buildCommitPrompt()
-		lang := m.client.GetLang()
+		prompts := m.commitPrompts()
 
-		// Select system prompt based on language (custom prompt takes precedence)
-		var systemPrompt string
-		if customPrompt := m.client.GetCommitPrompt(lang); customPrompt != "" {
-			systemPrompt = customPrompt
-		} else {
-			switch lang {
-			case consts.LLMLangZH:
-				systemPrompt = consts.LLMCommitPromptZH
-			case consts.LLMLangBilingual:
-				systemPrompt = consts.LLMCommitPromptBilingual
-			default:
-				systemPrompt = consts.LLMCommitPromptEN
+		// Candidates are generated in parallel with increasing temperature
+		n := m.candidates
+		if n < 1 {
+			n = 1
+		}
+		messages := make([]string, n)
+		errs := make([]error, n)
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				temperature := candidateTemperature(m.client.GetTemperature(), i)
+				messages[i], errs[i] = m.generateCandidate(prompts, temperature)
+			}(i)
+		}
+		wg.Wait()
+
+		// Keep successful candidates, fail only if none succeeded
+		var results []string
+		for i, message := range messages {
+			if errs[i] == nil {
+				results = append(results, message)
 			}
 		}
+		if len(results) == 0 {
+			return aiFinalGeneratedMsg{err: errs[0]}
+		}
+		return aiFinalGeneratedMsg{messages: results}
+	}
+}
+
+// commitPrompts holds the system and user prompts of the final message, as plain
+// text and, in structured mode, asking for a JSON object.
+type commitPrompts struct {
+	system     string
+	user       string
+	jsonSystem string
+	jsonUser   string
+}
+
+// commitPrompts builds the final message prompts once for all candidates.
+func (m aiModel) commitPrompts() commitPrompts {
+	p := commitPrompts{
+		system: m.commitSystemPrompt(),
+		user:   m.finalPrompt(),
+	}
+	if m.structured {
+		p.jsonSystem = m.commitJSONSystemPrompt()
+		p.jsonUser = m.finalJSONPrompt()
+	}
+	return p
+}
+
+// generateCandidate generates a single commit message with the given temperature.
+func (m aiModel) generateCandidate(p commitPrompts, temperature float64) (string, error) {
+	// Prefer validated JSON output, fall back to free text if unsupported
+	if m.structured {
+		message, err := m.generateStructured(p.jsonUser, p.jsonSystem, temperature)
+		if !errors.Is(err, errStructuredUnsupported) {
+			return message, err
+		}
+	}
+
+	opt := llm.GenerateOptions{
+		System:      p.system,
+		Temperature: temperature,
+	}
+	return m.client.Generate(m.ctx, m.client.GetModel(), p.user, opt)
+}
+
+// candidateTemperature returns the temperature for the i-th candidate:
+// the configured temperature for the first one, then raised by a fixed step (max 1.0).
+func candidateTemperature(base float64, i int) float64 {
+	t := base + float64(i)*consts.LLMCandidateTemperatureStep
+	if t > 1.0 {
+		t = 1.0
+	}
+	return t
+}
+
+// forRegenerate returns a copy of a finished model that keeps the file summaries
+// and only generates the final message again.
+func (m aiModel) forRegenerate() aiModel {
+	m = m.reset()
+	m.phase = "generating"
+	m.refineHistory = nil
+	return m
+}
+
+// forRefine returns a copy of a finished model that revises message according to instruction.
+func (m aiModel) forRefine(message, instruction string) aiModel {
+	m = m.reset()
+	m.phase = "refining"
+	m.refineCurrent = message
+	m.refineRequest = instruction
+	return m
+}
+
+// reset clears the result of a previous run so the model can run again.
+func (m aiModel) reset() aiModel {
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.runningCount = 0
+	m.finalMsgs = nil
+	m.done = false
+	m.cancelled = false
+	m.err = nil
+	return m
+}
 
+// finalPrompt returns the prompt used to generate the final commit message.
+func (m aiModel) finalPrompt() string {
+	if m.strategy == strategySinglePass {
+		return m.buildSinglePassPrompt()
+	}
+	return m.buildCommitPrompt(false)
+}
+
+// finalJSONPrompt returns the final prompt for structured output, with the
+// few-shot example as a JSON object.
+func (m aiModel) finalJSONPrompt() string {
+	if m.strategy == strategySinglePass {
+		return m.buildSinglePassPrompt()
+	}
+	return m.buildCommitPrompt(true)
+}
+
+// refineMessage sends the current message and the user's instruction as a follow-up turn
+// of the original conversation, including the most recent previous refinements.
+func (m aiModel) refineMessage() tea.Cmd {
+	history := []llm.Turn{{Role: llm.RoleUser, Content: m.finalPrompt()}}
+	history = append(history, m.refineHistory...)
+	history = append(history, llm.Turn{Role: llm.RoleAssistant, Content: m.refineCurrent})
+
+	return func() tea.Msg {
 		opt := llm.GenerateOptions{
-			System: systemPrompt,
+			System:  m.commitSystemPrompt(),
+			History: history,
+		}
+		message, err := m.client.Generate(m.ctx, m.client.GetModel(), refinePrompt(m.refineRequest), opt)
+		if err != nil {
+			return aiFinalGeneratedMsg{err: err}
 		}
-		message, err := m.client.Generate(m.ctx, m.client.GetModel(), prompt, opt)
-		return aiFinalGeneratedMsg{message: message, err: err}
+		return aiFinalGeneratedMsg{messages: []string{message}}
+	}
+}
+
+// refinePrompt returns the follow-up prompt for a refinement instruction.
+func refinePrompt(instruction string) string {
+	return fmt.Sprintf(consts.LLMRefinePrompt, instruction)
+}
+
+// appendRefineHistory records a completed refinement and keeps only the most recent ones.
+func appendRefineHistory(history []llm.Turn, message, instruction string) []llm.Turn {
+	history = append(history,
+		llm.Turn{Role: llm.RoleAssistant, Content: message},
+		llm.Turn{Role: llm.RoleUser, Content: refinePrompt(instruction)},
+	)
+	if limit := 2 * consts.LLMRefineHistoryTurns; len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+	return history
+}
+
+// commitSystemPrompt selects the commit system prompt for the configured language
+// (custom prompt takes precedence).
+func (m aiModel) commitSystemPrompt() string {
+	if customPrompt := m.client.GetCommitPrompt(); customPrompt != "" {
+		return customPrompt
 	}
+	return m.client.GetLang().CommitPrompt()
+}
+
+// commitJSONSystemPrompt selects the commit system prompt for structured output. A custom
+// prompt gets the JSON schema appended, the built-in ones are replaced by their JSON variant.
+func (m aiModel) commitJSONSystemPrompt() string {
+	types := strings.Join(commitTypeNames(), ", ")
+	if customPrompt := m.client.GetCommitPrompt(); customPrompt != "" {
+		return customPrompt + "\n\n" + fmt.Sprintf(consts.LLMCommitJSONInstruction, types, maxSubjectLen)
+	}
+	return m.client.GetLang().CommitJSONPrompt(types, maxSubjectLen)
 }
 
 // buildFilePrompt creates a prompt for analyzing a single file's changes.
@@ -185,68 +498,90 @@ Diff:
 Summary:`, file.Path, file.Diff)
 }
 
+// writeHistoryContext writes the branch name and recent branch commits so the model
+// can match the existing scope vocabulary and style.
+func (m aiModel) writeHistoryContext(sb *strings.Builder) {
+	h := m.history
+	if h.branch == "" && len(h.commits) == 0 {
+		return
+	}
+
+	sb.WriteString("Context:\n")
+	if h.branch != "" {
+		sb.WriteString("Branch: " + h.branch)
+		if h.branchType != "" {
+			sb.WriteString(" (suggests type: " + h.branchType + ")")
+		}
+		sb.WriteString("\n")
+	}
+	if len(h.commits) > 0 {
+		sb.WriteString("Recent commits on this branch (match their scope naming and style):\n")
+		for _, c := range h.commits {
+			sb.WriteString("- " + c + "\n")
+		}
+	}
+	sb.WriteString("\n")
+}
+
+// buildSinglePassPrompt creates a prompt containing the full diff of every analyzable file,
+// used when the total diff is small enough to skip per-file summarization.
+func (m aiModel) buildSinglePassPrompt() string {
+	var sb strings.Builder
+	m.writeHistoryContext(&sb)
+	sb.WriteString("Generate a commit message for the following staged changes.\n\n")
+
+	for i, file := range m.files {
+		if m.fileStatus[i] == 3 { // skipped
+			continue
+		}
+		sb.WriteString(file.Diff)
+		sb.WriteString("\n\n")
+	}
+
+	var others []string
+	for i, file := range m.files {
+		if m.fileStatus[i] == 3 {
+			others = append(others, fmt.Sprintf("- %s: %s", file.Path, m.summaries[i]))
+		}
+	}
+	if len(m.ignored) > 0 {
+		others = append(others, fmt.Sprintf("- also changed: %s", strings.Join(m.ignored, ", ")))
+	}
+	if len(others) > 0 {
+		sb.WriteString("Other changes:\n")
+		sb.WriteString(strings.Join(others, "\n"))
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString("Output:")
+	return sb.String()
+}
+
 // buildCommitPrompt creates a prompt for generating the final commit message.
-func (m aiModel) buildCommitPrompt() string {
+// With asJSON the few-shot example is shown as the JSON object to return.
+func (m aiModel) buildCommitPrompt(asJSON bool) string {
 	var sb strings.Builder
+	m.writeHistoryContext(&sb)
 
-	// Few-shot example based on language
-	lang := m.client.GetLang()
-	switch lang {
-	case consts.LLMLangZH:
-		sb.WriteString(`示例:
-输入:
-- auth.go: 添加了 JWT 验证
-- user.go: 添加了用户资料接口
-- docs.md: 更新了 API 文档
-
-输出:
-feat(api): 添加用户认证和资料功能
-
-- 实现 JWT token 验证
-- 添加用户资料接口
-- 更新 API 文档
-
-输入:
-`)
-	case consts.LLMLangBilingual:
-		sb.WriteString(`Example:
-Input:
-- auth.go: Added JWT validation
-- user.go: Added profile endpoint
-- docs.md: Updated API docs
-
-Output:
-feat(api): add authentication and user profile (添加用户认证和资料功能)
-
-- 实现 JWT token 验证
-- 添加用户资料接口
-- 更新 API 文档
-
-Input:
-`)
-	default:
-		sb.WriteString(`Example:
-Input:
-- auth.go: Added JWT validation
-- user.go: Added profile endpoint
-- docs.md: Updated API docs
-
-Output:
-feat(api): add authentication and user profile
-
-- implement JWT token validation
-- add user profile endpoint
-- update API documentation
-
-Input:
-`)
+	// Few-shot example in the output language
+	example, native := m.client.GetLang().Example()
+	if asJSON {
+		example = exampleJSON(example)
 	}
+	sb.WriteString("Example:\nInput:\n" + consts.LLMExampleInput + "\n\nOutput:\n" + example + "\n\n")
+	if !native {
+		sb.WriteString("(The example is in English; follow the language rules from the instructions.)\n\n")
+	}
+	sb.WriteString("Input:\n")
 
 	for i, summary := range m.summaries {
 		if summary != "" {
 			sb.WriteString(fmt.Sprintf("- %s: %s\n", m.files[i].Path, strings.TrimSpace(summary)))
 		}
 	}
+	if len(m.ignored) > 0 {
+		sb.WriteString(fmt.Sprintf("- also changed: %s\n", strings.Join(m.ignored, ", ")))
+	}
 
 	sb.WriteString("\nOutput:")
 
@@ -290,7 +625,16 @@ func (m aiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.summaries[msg.idx] = msg.summary
 		m.fileStatus[msg.idx] = 2 // done
 		m.completedCount++
+		if msg.cached {
+			m.fileCached[msg.idx] = true
+			m.cachedCount++
+		}
 
+		if m.completedCount >= len(m.files) && m.review {
+			// All files reviewed, findings are collected by the caller
+			m.done = true
+			return m, tea.Quit
+		}
 		if m.completedCount >= len(m.files) {
 			// All files analyzed, generate final message
 			m.phase = "generating"
@@ -310,10 +654,21 @@ func (m aiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 		m.done = true
-		if msg.err != nil {
+		if msg.err != nil && m.phase == "refining" {
+			m.err = fmt.Errorf("failed to refine commit message: %w", msg.err)
+		} else if msg.err != nil {
 			m.err = fmt.Errorf("failed to generate commit message: %w", msg.err)
 		} else {
-			m.finalMsg = msg.message
+			if m.phase == "refining" {
+				m.refineHistory = appendRefineHistory(m.refineHistory, m.refineCurrent, m.refineRequest)
+			}
+			for _, message := range msg.messages {
+				if m.pr == nil {
+					message = normalizeAIMessage(message)
+				}
+				m.finalMsgs = append(m.finalMsgs, message)
+			}
+			m.finalMsgs = uniqueStrings(m.finalMsgs)
 		}
 		return m, tea.Quit
 	}
@@ -335,7 +690,13 @@ func (m aiModel) View() string {
 		Background(common.ColorTitleBg).
 		Bold(true).
 		Padding(0, 1)
-	sb.WriteString(titleLayout.Render(titleStyle.Render("Auto Generate")))
+	title := "Auto Generate"
+	if m.pr != nil {
+		title = "PR Description"
+	} else if m.review {
+		title = "AI Review"
+	}
+	sb.WriteString(titleLayout.Render(titleStyle.Render(title)))
 	sb.WriteString("\n")
 
 	// Progress bar with status
@@ -344,12 +705,39 @@ func (m aiModel) View() string {
 
 	var status string
 	if m.phase == "analyzing" {
-		status = fmt.Sprintf("Analyzing... (%d/%d)", m.completedCount, len(m.files))
+		verb := "Analyzing"
+		if m.review {
+			verb = "Reviewing"
+		}
+		status = fmt.Sprintf("%s... (%d/%d)", verb, m.completedCount, len(m.files))
+		if m.skippedCount > 0 {
+			status += fmt.Sprintf(", %d skipped", m.skippedCount)
+		}
+		if m.cachedCount > 0 {
+			status += fmt.Sprintf(", %d cached", m.cachedCount)
+		}
+	} else if m.phase == "refining" {
+		status = "Refining commit message..."
 	} else {
-		status = "Generating commit message..."
+		status = "Generating commit message"
+		if m.pr != nil {
+			status = "Generating PR description"
+		} else if m.candidates > 1 {
+			status = fmt.Sprintf("Generating %d commit messages", m.candidates)
+		}
+		if m.strategy == strategySinglePass {
+			status += " (single pass)"
+		}
+		status += "..."
 	}
 	sb.WriteString(contentLayout.Render(progressBar + "  " + status))
-	sb.WriteString("\n\n")
+	sb.WriteString("\n")
+	if n := m.redactedCount(); n > 0 {
+		warnStyle := lipgloss.NewStyle().Foreground(common.ColorWarning)
+		sb.WriteString(contentLayout.Render(warnStyle.Render(fmt.Sprintf("%s %d secret(s) redacted before sending", common.SymbolWarning, n))))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
 
 	// File list with status (auto-scroll to show running files)
 	const maxVisibleFiles = 10
@@ -364,12 +752,20 @@ func (m aiModel) View() string {
 
 	for i := start; i < end; i++ {
 		file := m.files[i]
+		label := file.Path
 		var icon string
 		var style lipgloss.Style
 		switch m.fileStatus[i] {
+		case 3: // skipped
+			icon = common.SymbolSkipped
+			style = lipgloss.NewStyle().Foreground(common.ColorMuted)
+			label += " (" + m.plans[i].skipReason + ", skipped)"
 		case 2: // done
 			icon = common.SymbolSuccess
 			style = lipgloss.NewStyle().Foreground(common.ColorSuccess)
+			if i < len(m.fileCached) && m.fileCached[i] {
+				label += " (cached)"
+			}
 		case 1: // running
 			icon = common.SymbolRunning
 			style = lipgloss.NewStyle().Foreground(common.ColorWarning)
@@ -380,7 +776,15 @@ func (m aiModel) View() string {
 			icon = common.SymbolError
 			style = lipgloss.NewStyle().Foreground(common.ColorError)
 		}
-		sb.WriteString(contentLayout.Render(style.Render(icon + " " + file.Path)))
+		if m.fileStatus[i] != 3 && len(m.plans) > i && len(m.plans[i].chunks) > 1 {
+			label += fmt.Sprintf(" (%d parts)", len(m.plans[i].chunks))
+		}
+		line := style.Render(icon + " " + label)
+		if i < len(m.redactions) && len(m.redactions[i]) > 0 {
+			warnStyle := lipgloss.NewStyle().Foreground(common.ColorWarning)
+			line += warnStyle.Render(" [redacted: " + strings.Join(uniqueStrings(m.redactions[i]), ", ") + "]")
+		}
+		sb.WriteString(contentLayout.Render(line))
 		sb.WriteString("\n")
 	}
 
@@ -402,6 +806,26 @@ func (m aiModel) View() string {
 	return sb.String()
 }
 
+// analyzableTokens returns the estimated token count of all files that are not skipped.
+func analyzableTokens(files []git.FileDiff, plans []filePlan) int {
+	total := 0
+	for i, file := range files {
+		if plans[i].skipReason == "" {
+			total += llm.EstimateTokens(file.Diff)
+		}
+	}
+	return total
+}
+
+// redactedCount returns the total number of secrets masked across all files.
+func (m aiModel) redactedCount() int {
+	n := 0
+	for _, kinds := range m.redactions {
+		n += len(kinds)
+	}
+	return n
+}
+
 // calcVisibleRange calculates the visible file range for auto-scrolling.
 // It prioritizes showing running files in the visible window.
 func (m aiModel) calcVisibleRange(maxVisible int) (start, end int) {
@@ -480,27 +904,73 @@ func (m aiModel) renderProgressBar() string {
 
 // aiPreviewResult represents the result of preview interaction.
 type aiPreviewResult struct {
-	Message string
-	Action  string // "commit", "edit", "retry", "cancel"
+	Message     string
+	Action      string // "commit", "edit", "retry", "refine", "cancel"
+	Instruction string // refinement instruction for the "refine" action
 }
 
 // aiPreviewModel is the bubbletea model for AI preview.
 type aiPreviewModel struct {
-	message   string // original AI message (without SOB)
-	sob       string // Signed-off-by line
-	selected  int    // 0=Commit, 1=Edit, 2=Retry
-	committed bool
-	edit      bool
-	retry     bool
-	cancelled bool
+	candidates []string // all generated messages
+	current    int      // index of the candidate shown
+	message    string   // selected AI message (without SOB)
+	sob        string   // Signed-off-by line
+	strategy   string   // generation strategy, shown below the title
+	problems   []string // rules the message breaks that could not be repaired
+	err        error    // failure of the previous action, e.g. a refinement
+	selected   int      // 0=Commit, 1=Edit, 2=Retry, 3=Refine
+	committed  bool
+	edit       bool
+	retry      bool
+	refine     bool
+	refining   bool            // instruction input is active
+	input      textinput.Model // refinement instruction
+	diff       diffPane        // staged diff below the message
+	cancelled  bool
+	width      int
+	height     int
+}
+
+func newAIPreviewModel(candidates []string, strategy string) aiPreviewModel {
+	ti := textinput.New()
+	ti.Placeholder = "e.g. make it a fix, scope auth, mention the race condition"
+	ti.Prompt = "Refine: "
+	ti.CharLimit = 200
+
+	m := aiPreviewModel{
+		candidates: candidates,
+		sob:        git.CreateSOB(),
+		strategy:   strategy,
+		selected:   0,
+		input:      ti,
+		diff:       newDiffPane(),
+	}
+	return m.selectCandidate(0)
 }
 
-func newAIPreviewModel(message string) aiPreviewModel {
-	return aiPreviewModel{
-		message:  message,
-		sob:      git.CreateSOB(),
-		selected: 0,
+// selectCandidate shows the candidate at idx and re-checks it.
+func (m aiPreviewModel) selectCandidate(idx int) aiPreviewModel {
+	if idx < 0 || idx >= len(m.candidates) {
+		return m
 	}
+	m.current = idx
+	m.message = m.candidates[idx]
+	m.problems = checkAIMessage(m.message)
+	m.resizeDiff()
+	return m
+}
+
+// resizeDiff sizes the staged diff pane to the space left below the message.
+func (m *aiPreviewModel) resizeDiff() {
+	width, height := 76, 12
+	if m.width > 0 {
+		width = min(m.width-6, 100)
+	}
+	// Buttons, help and the pane frame take about 10 lines
+	if m.height > 0 {
+		height = m.height - lipgloss.Height(m.messageView()) - 10
+	}
+	m.diff.setSize(width, height)
 }
 
 func (m aiPreviewModel) Init() tea.Cmd {
@@ -508,8 +978,21 @@ func (m aiPreviewModel) Init() tea.Cmd {
 }
 
 func (m aiPreviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = size.Width
+		m.height = size.Height
+		m.resizeDiff()
+		return m, nil
+	}
+	if m.refining {
+		return m.updateRefineInput(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.diff.handleKey(msg) {
+			return m, nil
+		}
 		switch msg.String() {
 		case "ctrl+c", "esc", "q":
 			m.cancelled = true
@@ -522,8 +1005,13 @@ func (m aiPreviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.edit = true
 			case 2:
 				m.retry = true
+			case 3:
+				return m.startRefine()
 			}
 			return m, tea.Quit
+		case "f":
+			m.selected = 3
+			return m.startRefine()
 		case "c":
 			m.committed = true
 			return m, tea.Quit
@@ -538,21 +1026,109 @@ func (m aiPreviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.selected--
 			}
 		case "right", "l":
-			if m.selected < 2 {
+			if m.selected < 3 {
 				m.selected++
 			}
 		case "tab":
-			m.selected = (m.selected + 1) % 3
+			m.selected = (m.selected + 1) % 4
+		case "up", "k":
+			m = m.selectCandidate(m.current - 1)
+		case "down", "j":
+			m = m.selectCandidate(m.current + 1)
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			m = m.selectCandidate(int(msg.String()[0] - '1'))
 		}
 	}
 	return m, nil
 }
 
+// startRefine shows the instruction input.
+func (m aiPreviewModel) startRefine() (tea.Model, tea.Cmd) {
+	m.refining = true
+	m.input.SetValue("")
+	return m, m.input.Focus()
+}
+
+// updateRefineInput handles keys while the instruction input is active.
+func (m aiPreviewModel) updateRefineInput(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "ctrl+c":
+			m.cancelled = true
+			return m, tea.Quit
+		case "esc":
+			m.refining = false
+			m.input.Blur()
+			return m, nil
+		case "enter":
+			if strings.TrimSpace(m.input.Value()) == "" {
+				return m, nil
+			}
+			m.refine = true
+			return m, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
 func (m aiPreviewModel) View() string {
-	if m.committed || m.edit || m.retry || m.cancelled {
+	if m.committed || m.edit || m.retry || m.refine || m.cancelled {
 		return ""
 	}
 
+	var sb strings.Builder
+	sb.WriteString(m.messageView())
+
+	// Staged diff, sized in Update to the space left by the message
+	if m.diff.visible {
+		sb.WriteString(lipgloss.NewStyle().PaddingLeft(2).PaddingTop(1).Render(m.diff.View()))
+		sb.WriteString("\n")
+	}
+
+	// Refinement instruction input replaces the buttons while active
+	if m.refining {
+		inputLayout := lipgloss.NewStyle().PaddingLeft(2).PaddingTop(1)
+		sb.WriteString(inputLayout.Render(m.input.View()))
+		sb.WriteString("\n")
+
+		helpStyle := lipgloss.NewStyle().
+			Foreground(common.ColorMuted).
+			PaddingLeft(2).
+			PaddingTop(1)
+		sb.WriteString(helpStyle.Render("enter send • esc back"))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	// Buttons
+	buttonLayout := lipgloss.NewStyle().PaddingLeft(2).PaddingTop(1)
+	sb.WriteString(buttonLayout.Render(m.renderButtons()))
+	sb.WriteString("\n")
+
+	// Help
+	helpStyle := lipgloss.NewStyle().
+		Foreground(common.ColorMuted).
+		PaddingLeft(2).
+		PaddingTop(1)
+	help := "←/→ select • enter confirm • c commit • e edit • r retry • f refine • ctrl+g diff • q quit"
+	if m.diff.visible {
+		help = "alt+n/alt+p diff file • pgup/pgdn scroll • " + help
+	}
+	if len(m.candidates) > 1 {
+		help = "↑/↓ candidate • " + help
+	}
+	sb.WriteString(helpStyle.Render(help))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// messageView renders the title, the candidates and the selected message
+// with the rules it breaks.
+func (m aiPreviewModel) messageView() string {
 	var sb strings.Builder
 
 	// Title
@@ -565,6 +1141,19 @@ func (m aiPreviewModel) View() string {
 	sb.WriteString(titleLayout.Render(titleStyle.Render("Auto Generated Commit")))
 	sb.WriteString("\n")
 
+	// Strategy used to generate the message
+	if m.strategy != "" {
+		strategyStyle := lipgloss.NewStyle().Foreground(common.ColorMuted).PaddingLeft(2)
+		sb.WriteString(strategyStyle.Render("Strategy: " + m.strategy))
+		sb.WriteString("\n\n")
+	}
+
+	// Candidate list, selected one is shown in full below
+	if len(m.candidates) > 1 {
+		sb.WriteString(m.renderCandidates())
+		sb.WriteString("\n")
+	}
+
 	// Content with left border
 	contentLayout := lipgloss.NewStyle().PaddingLeft(2)
 	contentStyle := lipgloss.NewStyle().
@@ -580,22 +1169,48 @@ func (m aiPreviewModel) View() string {
 	sb.WriteString(contentLayout.Render(contentStyle.Render(displayMsg)))
 	sb.WriteString("\n")
 
-	// Buttons
-	buttonLayout := lipgloss.NewStyle().PaddingLeft(2).PaddingTop(1)
-	sb.WriteString(buttonLayout.Render(m.renderButtons()))
-	sb.WriteString("\n")
+	// Rules the message still breaks
+	if len(m.problems) > 0 {
+		problemStyle := lipgloss.NewStyle().Foreground(common.ColorWarning).PaddingLeft(2)
+		sb.WriteString("\n")
+		for _, problem := range m.problems {
+			sb.WriteString(problemStyle.Render(common.SymbolWarning + " " + problem))
+			sb.WriteString("\n")
+		}
+	}
 
-	// Help
-	helpStyle := lipgloss.NewStyle().
-		Foreground(common.ColorMuted).
-		PaddingLeft(2).
-		PaddingTop(1)
-	sb.WriteString(helpStyle.Render("←/→ select • enter confirm • c commit • e edit • r retry • q quit"))
-	sb.WriteString("\n")
+	// Failure of the previous action, the message can still be used
+	if m.err != nil {
+		errStyle := lipgloss.NewStyle().Foreground(common.ColorError).PaddingLeft(2).PaddingTop(1)
+		sb.WriteString(errStyle.Render(common.SymbolError + " " + m.err.Error()))
+		sb.WriteString("\n")
+	}
 
 	return sb.String()
 }
 
+// renderCandidates renders the subject line of every candidate with the current one highlighted.
+func (m aiPreviewModel) renderCandidates() string {
+	layout := lipgloss.NewStyle().PaddingLeft(2)
+	activeStyle := lipgloss.NewStyle().Foreground(common.ColorPrimary).Bold(true)
+	inactiveStyle := lipgloss.NewStyle().Foreground(common.ColorMuted)
+
+	var sb strings.Builder
+	for i, candidate := range m.candidates {
+		subject := candidate
+		if lines := splitLines(candidate); len(lines) > 0 {
+			subject = lines[0]
+		}
+		if i == m.current {
+			sb.WriteString(layout.Render(activeStyle.Render(fmt.Sprintf("▸ %d. %s", i+1, subject))))
+		} else {
+			sb.WriteString(layout.Render(inactiveStyle.Render(fmt.Sprintf("  %d. %s", i+1, subject))))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
 func (m aiPreviewModel) renderButtons() string {
 	activeStyle := lipgloss.NewStyle().
 		Foreground(common.ColorTitleFg).
@@ -605,7 +1220,7 @@ func (m aiPreviewModel) renderButtons() string {
 
 	inactiveStyle := lipgloss.NewStyle().
 		Foreground(common.ColorMuted).
-		Background(lipgloss.AdaptiveColor{Light: "#DDDDDD", Dark: "#3a3a3a"}).
+		Background(common.ColorButton).
 		Padding(0, 2)
 
 	editActiveStyle := lipgloss.NewStyle().
@@ -620,36 +1235,45 @@ func (m aiPreviewModel) renderButtons() string {
 		Bold(true).
 		Padding(0, 2)
 
-	var commitBtn, editBtn, retryBtn string
+	refineActiveStyle := lipgloss.NewStyle().
+		Foreground(common.ColorTitleFg).
+		Background(common.ColorBorder).
+		Bold(true).
+		Padding(0, 2)
+
+	commitBtn := inactiveStyle.Render("  Commit  ")
+	editBtn := inactiveStyle.Render("  Edit  ")
+	retryBtn := inactiveStyle.Render("  Retry  ")
+	refineBtn := inactiveStyle.Render("  Refine  ")
 	switch m.selected {
 	case 0:
 		commitBtn = activeStyle.Render("  Commit  ")
-		editBtn = inactiveStyle.Render("  Edit  ")
-		retryBtn = inactiveStyle.Render("  Retry  ")
 	case 1:
-		commitBtn = inactiveStyle.Render("  Commit  ")
 		editBtn = editActiveStyle.Render("  Edit  ")
-		retryBtn = inactiveStyle.Render("  Retry  ")
 	case 2:
-		commitBtn = inactiveStyle.Render("  Commit  ")
-		editBtn = inactiveStyle.Render("  Edit  ")
 		retryBtn = retryActiveStyle.Render("  Retry  ")
+	case 3:
+		refineBtn = refineActiveStyle.Render("  Refine  ")
 	}
 
-	return commitBtn + "  " + editBtn + "  " + retryBtn
+	return commitBtn + "  " + editBtn + "  " + retryBtn + "  " + refineBtn
 }
 
-// runAIPreview shows the AI-generated message and returns user action.
-func runAIPreview(message string) aiPreviewResult {
-	m := newAIPreviewModel(message)
+// runAIPreview shows the AI-generated candidates and returns user action
+// with the selected message. A non-nil err is shown below the message.
+func runAIPreview(candidates []string, strategy string, err error) aiPreviewResult {
+	m := newAIPreviewModel(candidates, strategy)
+	m.err = err
+	m.resizeDiff()
 	p := tea.NewProgram(m)
 
 	finalModel, err := p.Run()
 	if err != nil {
-		return aiPreviewResult{Message: message, Action: "cancel"}
+		return aiPreviewResult{Message: m.message, Action: "cancel"}
 	}
 
 	result := finalModel.(aiPreviewModel)
+	message := result.message
 	if result.cancelled {
 		return aiPreviewResult{Message: message, Action: "cancel"}
 	}
@@ -659,32 +1283,78 @@ func runAIPreview(message string) aiPreviewResult {
 	if result.retry {
 		return aiPreviewResult{Message: message, Action: "retry"}
 	}
+	if result.refine {
+		return aiPreviewResult{Message: message, Action: "refine", Instruction: strings.TrimSpace(result.input.Value())}
+	}
 	return aiPreviewResult{Message: message, Action: "commit"}
 }
 
 // runAIGenerate runs the AI generation flow.
-func runAIGenerate() aiResult {
+// If noCache is true, file summaries are neither read from nor written to the cache.
+func runAIGenerate(noCache bool) aiResult {
+	input, err := prepareStagedInput(noCache)
+	if err != nil {
+		return aiResult{Err: err}
+	}
+	input.history = loadBranchHistory()
+
+	// Run the TUI
+	return runAIModel(newAIModel(input, llm.NewClient()))
+}
+
+// prepareStagedInput splits the staged diff by file, drops ignored files and masks secrets.
+func prepareStagedInput(noCache bool) (aiInput, error) {
 	// Get staged diff
 	contextLines := llm.GetDiffContext()
 	diff, err := git.GetStagedDiff(contextLines)
 	if err != nil {
-		return aiResult{Err: fmt.Errorf("failed to get staged diff: %w", err)}
+		return aiInput{}, fmt.Errorf("failed to get staged diff: %w", err)
 	}
 	if diff == "" {
-		return aiResult{Err: fmt.Errorf("no staged changes")}
+		return aiInput{}, fmt.Errorf("no staged changes")
 	}
 
 	// Split diff by file
 	files := git.SplitDiffByFile(diff)
 	if len(files) == 0 {
-		return aiResult{Err: fmt.Errorf("no files in diff")}
+		return aiInput{}, fmt.Errorf("no files in diff")
 	}
 
-	// Create LLM client
-	client := llm.NewClient()
+	// Exclude ignored files from analysis (still listed in the final prompt).
+	// If every file is ignored, the message is generated from the path list alone.
+	files, ignored := git.FilterIgnored(files, git.GetIgnorePatterns())
 
-	// Run the TUI
-	m := newAIModel(files, client)
+	// Mask secrets before any request leaves the machine
+	redactions, err := redactFiles(files)
+	if err != nil {
+		return aiInput{}, err
+	}
+
+	var cache *llm.Cache
+	if !noCache {
+		cache = llm.NewDefaultCache()
+	}
+
+	return aiInput{
+		files:      files,
+		ignored:    ignored,
+		redactions: redactions,
+		cache:      cache,
+	}, nil
+}
+
+// runAIRegenerate generates new final messages from the file summaries of a previous run.
+func runAIRegenerate(prev aiModel) aiResult {
+	return runAIModel(prev.forRegenerate())
+}
+
+// runAIRefine revises message according to instruction as a follow-up turn of a previous run.
+func runAIRefine(prev aiModel, message, instruction string) aiResult {
+	return runAIModel(prev.forRefine(message, instruction))
+}
+
+// runAIModel runs the AI progress TUI until the final messages are generated.
+func runAIModel(m aiModel) aiResult {
 	p := tea.NewProgram(m)
 
 	finalModel, err := p.Run()
@@ -700,5 +1370,68 @@ func runAIGenerate() aiResult {
 		return aiResult{Err: result.err}
 	}
 
-	return aiResult{Message: result.finalMsg}
+	return aiResult{Messages: result.finalMsgs, Strategy: result.strategy, state: &result}
+}
+
+// redactFiles masks secrets in the diffs of files that will be analyzed, in place.
+// Returns the kinds of secrets masked per file, or an error if llm-redact-block
+// is enabled and any secret was found.
+func redactFiles(files []git.FileDiff) ([][]string, error) {
+	redactor, err := llm.NewRedactor(
+		config.GetBool(config.GitConfigLLMRedact, true),
+		config.GetStrings(config.GitConfigLLMRedactPatterns),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	redactions := make([][]string, len(files))
+	var found []string
+	for i := range files {
+		// Skipped files never reach the LLM
+		if fileSkipReason(files[i]) != "" {
+			continue
+		}
+		files[i].Diff, redactions[i] = redactor.Redact(files[i].Path, files[i].Diff)
+		if len(redactions[i]) > 0 {
+			found = append(found, fmt.Sprintf("%s (%s)", files[i].Path, strings.Join(uniqueStrings(redactions[i]), ", ")))
+		}
+	}
+
+	if len(found) > 0 && config.GetBool(config.GitConfigLLMRedactBlock, false) {
+		return nil, fmt.Errorf("secrets detected in staged changes, generation blocked:\n%s", strings.Join(found, "\n"))
+	}
+
+	return redactions, nil
+}
+
+// uniqueStrings returns the distinct values in order of first appearance.
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var result []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// loadBranchHistory collects the current branch and its recent commits for prompt context.
+// Returns an empty history if disabled via llm-history-context.
+func loadBranchHistory() branchHistory {
+	if !config.GetBool(config.GitConfigLLMHistoryContext, true) {
+		return branchHistory{}
+	}
+
+	var h branchHistory
+	if branch, err := git.CurrentBranch(); err == nil {
+		h.branch = branch
+		h.branchType = git.ParseBranchType(branch)
+	}
+	if n := config.GetInt(config.GitConfigLLMHistoryCommits, consts.LLMDefaultHistoryCommits); n > 0 {
+		h.commits, _ = git.GetPreviousCommits(n)
+	}
+	return h
 }
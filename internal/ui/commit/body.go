@@ -0,0 +1,94 @@
+package commit
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// listMarkerRe matches a list item marker: "- ", "* ", "+ ", "1. " or "1) ".
+var listMarkerRe = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+`)
+
+// listMarker returns the leading list marker of a line including indentation
+// and trailing spaces, or "" if the line is not a list item.
+func listMarker(line string) string {
+	return listMarkerRe.FindString(line)
+}
+
+// nextListMarker returns the marker of the item following one with the given
+// marker; numbered markers are incremented.
+func nextListMarker(marker string) string {
+	m := listMarkerRe.FindStringSubmatch(marker)
+	if m == nil {
+		return ""
+	}
+	bullet := m[2]
+	if n, err := strconv.Atoi(bullet[:len(bullet)-1]); err == nil {
+		bullet = strconv.Itoa(n+1) + bullet[len(bullet)-1:]
+	}
+	return m[1] + bullet + marker[len(m[1])+len(m[2]):]
+}
+
+// wordRe matches a word with the whitespace before it.
+var wordRe = regexp.MustCompile(`(\s*)(\S+)`)
+
+// wrapBody hard-wraps body lines longer than width at word boundaries.
+// Continuation lines of list items are indented to the item text. Code is
+// left as written: fenced blocks and lines indented with a tab or four spaces.
+func wrapBody(body string, width int) string {
+	if body == "" {
+		return body
+	}
+
+	var out []string
+	inFence := false
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			out = append(out, line)
+			continue
+		}
+		if inFence || isCodeLine(line) {
+			out = append(out, line)
+			continue
+		}
+
+		line = strings.TrimRight(line, " \t")
+		if lipgloss.Width(line) <= width {
+			out = append(out, line)
+			continue
+		}
+
+		indent := listMarker(line)
+		if indent == "" {
+			indent = line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		}
+		cont := strings.Repeat(" ", lipgloss.Width(indent))
+
+		// Break at whitespace only, keeping the spacing between words on a line
+		current := indent
+		for i, m := range wordRe.FindAllStringSubmatch(line[len(indent):], -1) {
+			space, word := m[1], m[2]
+			if i > 0 && lipgloss.Width(current)+lipgloss.Width(space)+lipgloss.Width(word) > width {
+				out = append(out, current)
+				current = cont + word
+				continue
+			}
+			if i > 0 {
+				current += space
+			}
+			current += word
+		}
+		out = append(out, current)
+	}
+	return strings.Join(out, "\n")
+}
+
+// isCodeLine reports whether a line is an indented code line: it starts with
+// a tab or four spaces and is not a list item.
+func isCodeLine(line string) bool {
+	return (strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "    ")) && listMarker(line) == ""
+}
@@ -0,0 +1,183 @@
+package commit
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/mritd/gitflow-toolkit/v3/internal/git"
+	"github.com/mritd/gitflow-toolkit/v3/internal/llm"
+)
+
+// Skip reasons for files that are not sent to the LLM.
+const (
+	skipReasonBinary    = "binary file"
+	skipReasonLock      = "lock file"
+	skipReasonGenerated = "generated file"
+)
+
+// lockFiles lists dependency lock files by base name.
+var lockFiles = map[string]bool{
+	"go.sum":              true,
+	"go.work.sum":         true,
+	"package-lock.json":   true,
+	"npm-shrinkwrap.json": true,
+	"yarn.lock":           true,
+	"pnpm-lock.yaml":      true,
+	"bun.lockb":           true,
+	"Cargo.lock":          true,
+	"Gemfile.lock":        true,
+	"composer.lock":       true,
+	"poetry.lock":         true,
+	"Pipfile.lock":        true,
+	"uv.lock":             true,
+	"flake.lock":          true,
+	"mix.lock":            true,
+	"pubspec.lock":        true,
+	"Podfile.lock":        true,
+}
+
+// generatedPatterns lists base name patterns (path.Match syntax) of generated files.
+var generatedPatterns = []string{
+	"*.pb.go",
+	"*.pb.gw.go",
+	"*_generated.go",
+	"*.gen.go",
+	"zz_generated.*",
+	"*.min.js",
+	"*.min.css",
+	"*.map",
+	"*.snap",
+}
+
+// filePlan describes how a single file is sent to the LLM.
+type filePlan struct {
+	skipReason string   // non-empty if the file is not analyzed
+	chunks     []string // diff chunks, each analyzed in a separate request
+}
+
+// planFile decides whether a file is skipped, analyzed in one request,
+// or split into hunk groups that each fit within maxTokens.
+func planFile(file git.FileDiff, maxTokens int) filePlan {
+	if reason := fileSkipReason(file); reason != "" {
+		return filePlan{skipReason: reason}
+	}
+	return filePlan{chunks: splitFileDiff(file.Diff, maxTokens)}
+}
+
+// fileSkipReason returns why a file should not be sent to the LLM,
+// or empty string if it should be analyzed.
+func fileSkipReason(file git.FileDiff) string {
+	if file.IsBinary() {
+		return skipReasonBinary
+	}
+
+	base := path.Base(file.Path)
+	if lockFiles[base] {
+		return skipReasonLock
+	}
+	for _, pattern := range generatedPatterns {
+		if ok, _ := path.Match(pattern, base); ok {
+			return skipReasonGenerated
+		}
+	}
+
+	// Honor the conventional generated-code marker (https://go.dev/s/generatedcode)
+	if strings.Contains(file.Diff, "\n+// Code generated ") && strings.Contains(file.Diff, "DO NOT EDIT.") {
+		return skipReasonGenerated
+	}
+
+	return ""
+}
+
+// skippedSummary returns the summary used in the commit prompt for a skipped file.
+func skippedSummary(file git.FileDiff, reason string) string {
+	added, removed := countChangedLines(file.Diff)
+	if reason == skipReasonBinary {
+		return "updated binary file"
+	}
+	return fmt.Sprintf("updated %s (+%d/-%d lines, not analyzed)", reason, added, removed)
+}
+
+// countChangedLines counts added and removed lines in a diff, excluding file headers.
+// Only "---"/"+++" lines before the first hunk of a file are headers; inside a
+// hunk they are removed or added content such as "-- comment" or "++i".
+func countChangedLines(diff string) (added, removed int) {
+	inHeader := true
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git"):
+			inHeader = true
+		case strings.HasPrefix(line, "@@"):
+			inHeader = false
+		case inHeader && (strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---")):
+		case strings.HasPrefix(line, "+"):
+			added++
+		case strings.HasPrefix(line, "-"):
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// splitFileDiff splits a file diff into chunks whose estimated token count fits within maxTokens.
+// Each chunk repeats the file header followed by as many whole hunks as fit.
+// A single hunk larger than the budget is truncated.
+func splitFileDiff(diff string, maxTokens int) []string {
+	if maxTokens <= 0 || llm.EstimateTokens(diff) <= maxTokens {
+		return []string{diff}
+	}
+
+	header, hunks := git.SplitDiffHunks(diff)
+	if len(hunks) == 0 {
+		return []string{truncateToTokens(diff, maxTokens)}
+	}
+
+	budget := maxTokens - llm.EstimateTokens(header)
+	if budget <= 0 {
+		budget = maxTokens / 2
+	}
+
+	var chunks []string
+	var group []string
+	groupTokens := 0
+
+	flush := func() {
+		if len(group) > 0 {
+			chunks = append(chunks, header+"\n"+strings.Join(group, "\n"))
+			group = nil
+			groupTokens = 0
+		}
+	}
+
+	for _, hunk := range hunks {
+		tokens := llm.EstimateTokens(hunk)
+		if tokens > budget {
+			flush()
+			chunks = append(chunks, header+"\n"+truncateToTokens(hunk, budget))
+			continue
+		}
+		if groupTokens+tokens > budget {
+			flush()
+		}
+		group = append(group, hunk)
+		groupTokens += tokens
+	}
+	flush()
+
+	return chunks
+}
+
+// truncateToTokens keeps whole lines until the token budget is reached
+// and appends a marker with the number of dropped lines.
+func truncateToTokens(s string, maxTokens int) string {
+	lines := strings.Split(s, "\n")
+	used := 0
+	for i, line := range lines {
+		used += llm.EstimateTokens(line + "\n")
+		if used > maxTokens {
+			return strings.Join(lines[:i], "\n") + fmt.Sprintf("\n... (%d more lines truncated)", len(lines)-i)
+		}
+	}
+	return s
+}
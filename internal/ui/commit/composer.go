@@ -0,0 +1,423 @@
+package commit
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/mritd/gitflow-toolkit/v3/internal/git"
+	"github.com/mritd/gitflow-toolkit/v3/internal/ui/common"
+)
+
+// composerStep is a step of the commit composer.
+type composerStep int
+
+const (
+	stepType composerStep = iota
+	stepFields
+	stepConfirm
+)
+
+// composerStepNames are the step labels shown above the form.
+var composerStepNames = []string{"Type", "Message", "Confirm"}
+
+const (
+	// composerSideBySide is the minimum width for showing the preview next to the form.
+	composerSideBySide = 100
+	// composerHeaderLines is the height of the step bar.
+	composerHeaderLines = 2
+)
+
+// composerStyles holds the composer styles, built from the current theme.
+type composerStyles struct {
+	stepsLayout  lipgloss.Style
+	stepActive   lipgloss.Style
+	stepDone     lipgloss.Style
+	stepPending  lipgloss.Style
+	previewTitle lipgloss.Style
+	previewBox   lipgloss.Style
+	confirmTitle lipgloss.Style
+	titleLayout  lipgloss.Style
+	buttonLayout lipgloss.Style
+	help         lipgloss.Style
+}
+
+func newComposerStyles() composerStyles {
+	return composerStyles{
+		stepsLayout: lipgloss.NewStyle().
+			Padding(1, 0, 0, 2),
+		stepActive: lipgloss.NewStyle().
+			Foreground(common.ColorPrimary).
+			Bold(true),
+		stepDone: lipgloss.NewStyle().
+			Foreground(common.ColorSuccess),
+		stepPending: lipgloss.NewStyle().
+			Foreground(common.ColorMuted),
+		previewTitle: lipgloss.NewStyle().
+			Foreground(common.ColorPrimary).
+			Bold(true).
+			PaddingBottom(1),
+		previewBox: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(common.ColorBorder).
+			Padding(0, 1).
+			MarginTop(1),
+		confirmTitle: lipgloss.NewStyle().
+			Foreground(common.ColorTitleFg).
+			Background(common.ColorTitleBg).
+			Bold(true).
+			Padding(0, 1),
+		titleLayout: lipgloss.NewStyle().
+			Padding(1, 0, 1, 2),
+		buttonLayout: lipgloss.NewStyle().
+			PaddingLeft(2),
+		help: lipgloss.NewStyle().
+			Foreground(common.ColorMuted).
+			PaddingLeft(2).
+			PaddingTop(1),
+	}
+}
+
+// composerModel composes a commit on a single screen: the type selector, the
+// message form and the confirmation on the left, a live preview on the right.
+// Esc goes back one step without losing what was entered.
+type composerModel struct {
+	step       composerStep
+	selector   selectorModel
+	fields     inputsModel
+	diff       diffPane // staged diff shown instead of the preview
+	commitType string
+	sob        string
+	selected   int // 0 = Commit, 1 = Cancel
+	styles     composerStyles
+	width      int
+	height     int
+
+	choice     string            // aiGenerateChoice when switching to AI generation
+	message    git.CommitMessage // the message to commit once confirmed
+	confirmed  bool
+	cancelled  bool
+	wantEditor bool // open the external editor for the body, then resume
+}
+
+func newComposerModel(initialType string) composerModel {
+	return composerModel{
+		selector: newSelectorModel(initialType),
+		fields:   newInputsModel(initialType),
+		diff:     newDiffPane(),
+		sob:      git.CreateSOB(),
+		styles:   newComposerStyles(),
+	}
+}
+
+func (m composerModel) Init() tea.Cmd {
+	return m.fields.Init()
+}
+
+func (m composerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, m.resize()
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.cancelled = true
+			return m, tea.Quit
+		}
+		if m.diff.handleKey(msg) {
+			return m, nil
+		}
+		switch m.step {
+		case stepType:
+			return m.updateType(msg)
+		case stepFields:
+			return m.updateFields(msg)
+		default:
+			return m.updateConfirm(msg)
+		}
+	}
+
+	// Cursor blinks and spinner ticks
+	model, cmd := m.fields.Update(msg)
+	m.fields = model.(inputsModel)
+	return m, cmd
+}
+
+// resize lays out the form and the preview for the current window size.
+func (m *composerModel) resize() tea.Cmd {
+	size := tea.WindowSizeMsg{Width: m.formWidth(), Height: m.height - composerHeaderLines}
+	// Border and padding take 4 columns and 2 lines, the pane title 2 more lines;
+	// below the form the pane gets a few lines only
+	diffHeight := m.height - composerHeaderLines - 5
+	if !m.sideBySide() {
+		diffHeight = min(diffHeight, 8)
+	}
+	m.diff.setSize(m.previewWidth()-4, diffHeight)
+
+	model, _ := m.selector.Update(size)
+	m.selector = model.(selectorModel)
+	model, cmd := m.fields.Update(size)
+	m.fields = model.(inputsModel)
+	return cmd
+}
+
+// sideBySide reports whether the preview fits next to the form.
+func (m composerModel) sideBySide() bool {
+	return m.width >= composerSideBySide
+}
+
+// formWidth returns the width of the form column.
+func (m composerModel) formWidth() int {
+	if !m.sideBySide() {
+		return m.width
+	}
+	return m.width - m.previewWidth()
+}
+
+// previewWidth returns the width of the preview column including its border.
+func (m composerModel) previewWidth() int {
+	if !m.sideBySide() {
+		return m.width
+	}
+	return m.width * 2 / 5
+}
+
+func (m composerModel) updateType(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.cancelled = true
+		return m, tea.Quit
+
+	case "a":
+		m.choice = aiGenerateChoice
+		return m, tea.Quit
+
+	case "enter":
+		choice := m.selector.selected()
+		if choice == aiGenerateChoice {
+			m.choice = choice
+			return m, tea.Quit
+		}
+		m.commitType = choice
+		m.fields.title = "Commit Type: " + strings.ToUpper(choice)
+		m.step = stepFields
+		return m, m.fields.updateFocus()
+	}
+
+	model, cmd := m.selector.Update(msg)
+	m.selector = model.(selectorModel)
+	return m, cmd
+}
+
+func (m composerModel) updateFields(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.step = stepType
+		return m, nil
+
+	case "ctrl+e":
+		if m.fields.focusIndex == bodyIndex {
+			m.wantEditor = true
+			return m, tea.Quit
+		}
+
+	case "enter":
+		if m.fields.focusIndex == len(m.fields.inputs) {
+			// The form shows the validation error next to the Submit button
+			if m.fields.validate() != nil {
+				return m, nil
+			}
+			msg, err := BuildMessage(m.composedFields())
+			if err != nil {
+				return m, nil
+			}
+			msg.SOB = m.sob
+			m.message = msg
+			m.selected = 0
+			m.step = stepConfirm
+			return m, nil
+		}
+	}
+
+	model, cmd := m.fields.Update(msg)
+	m.fields = model.(inputsModel)
+	return m, cmd
+}
+
+func (m composerModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "b":
+		m.step = stepFields
+	case "n":
+		m.cancelled = true
+		return m, tea.Quit
+	case "y":
+		m.confirmed = true
+		return m, tea.Quit
+	case "enter":
+		if m.selected == 0 {
+			m.confirmed = true
+		} else {
+			m.cancelled = true
+		}
+		return m, tea.Quit
+	case "left", "h":
+		m.selected = 0
+	case "right", "l":
+		m.selected = 1
+	case "tab":
+		m.selected = (m.selected + 1) % 2
+	}
+	return m, nil
+}
+
+// composedFields returns the commit type and the form values.
+func (m composerModel) composedFields() Fields {
+	r := m.fields.result()
+	return Fields{
+		Type:    m.commitType,
+		Scope:   r.scope,
+		Subject: r.subject,
+		Body:    r.body,
+		Footer:  r.footer,
+	}
+}
+
+// previewMessage returns the message as it would be committed, with
+// placeholders for the fields that are still empty.
+func (m composerModel) previewMessage() git.CommitMessage {
+	if m.step == stepConfirm {
+		return m.message
+	}
+
+	f := m.composedFields()
+	if m.step == stepType {
+		if choice := m.selector.selected(); choice != aiGenerateChoice {
+			f.Type = choice
+		}
+	}
+	if f.Scope == "" {
+		f.Scope = "scope"
+	}
+	if f.Subject == "" {
+		f.Subject = "subject"
+	}
+	if f.Body == "" {
+		f.Body = f.Subject
+	}
+	return git.CommitMessage{
+		Type:    f.Type,
+		Scope:   f.Scope,
+		Subject: f.Subject,
+		Body:    f.Body,
+		Footer:  f.Footer,
+		SOB:     m.sob,
+	}
+}
+
+func (m composerModel) View() string {
+	if m.confirmed || m.cancelled || m.wantEditor || m.choice != "" {
+		return ""
+	}
+
+	var form string
+	switch m.step {
+	case stepType:
+		form = m.selector.View()
+	case stepFields:
+		form = m.fields.View()
+	default:
+		form = m.confirmView()
+	}
+	form = lipgloss.NewStyle().MaxWidth(m.formWidth()).Render(form)
+
+	side := m.previewView()
+	if m.diff.visible {
+		side = m.diff.View()
+	}
+
+	var body string
+	if m.sideBySide() {
+		body = lipgloss.JoinHorizontal(lipgloss.Top, lipgloss.NewStyle().Width(m.formWidth()).Render(form), side)
+	} else {
+		body = lipgloss.JoinVertical(lipgloss.Left, form, side)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, m.stepsView(), body) + "\n"
+}
+
+// stepsView renders the step bar, e.g. "✓ Type › Message › Confirm".
+func (m composerModel) stepsView() string {
+	parts := make([]string, len(composerStepNames))
+	for i, name := range composerStepNames {
+		switch {
+		case composerStep(i) < m.step:
+			parts[i] = m.styles.stepDone.Render("✓ " + name)
+		case composerStep(i) == m.step:
+			parts[i] = m.styles.stepActive.Render(name)
+		default:
+			parts[i] = m.styles.stepPending.Render(name)
+		}
+	}
+	hint := "ctrl+g staged diff"
+	if m.diff.visible {
+		hint = "ctrl+g preview • alt+n/alt+p file • pgup/pgdn scroll"
+	}
+	steps := strings.Join(parts, m.styles.stepPending.Render(" › "))
+	return m.styles.stepsLayout.Render(steps + m.styles.stepPending.Render("   "+hint))
+}
+
+// previewView renders the live preview of the commit message.
+func (m composerModel) previewView() string {
+	// Border and padding take 4 columns
+	width := m.previewWidth() - 4
+	if width < 20 {
+		width = 20
+	}
+	msg := m.previewMessage()
+	content := common.FormatCommitMessage(common.CommitMessageContent{
+		Type:    msg.Type,
+		Scope:   msg.Scope,
+		Subject: msg.Subject,
+		Body:    msg.Body,
+		Footer:  msg.Footer,
+		SOB:     msg.SOB,
+	}, width)
+	return m.styles.previewBox.Width(width + 2).Render(m.styles.previewTitle.Render("Preview") + "\n" + content)
+}
+
+// confirmView renders the confirmation step.
+func (m composerModel) confirmView() string {
+	title := m.styles.titleLayout.Render(m.styles.confirmTitle.Render("Commit Preview"))
+	buttons := m.styles.buttonLayout.Render(renderButtons(m.selected))
+	help := m.styles.help.Render("y/enter confirm • esc back • n cancel • ←/→ select")
+	return lipgloss.JoinVertical(lipgloss.Left, title, buttons, help)
+}
+
+// runComposer runs the commit composer, leaving it to edit the body in the
+// external editor and resuming with the same state.
+func runComposer(initialType string) (composerModel, error) {
+	m := newComposerModel(initialType)
+
+	for {
+		finalModel, err := tea.NewProgram(m).Run()
+		if err != nil {
+			return m, err
+		}
+
+		m = finalModel.(composerModel)
+		if !m.wantEditor {
+			return m, nil
+		}
+
+		area := &m.fields.inputs[bodyIndex].area
+		if content, err := runExternalEditor(area.Value()); err == nil {
+			area.SetValue(content)
+			m.fields.bodyEdited = true
+		}
+		m.wantEditor = false
+	}
+}
@@ -0,0 +1,218 @@
+package commit
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+
+	"github.com/mritd/gitflow-toolkit/v3/internal/git"
+	"github.com/mritd/gitflow-toolkit/v3/internal/ui/common"
+)
+
+// diffPaneContext is the number of context lines in the staged diff pane.
+const diffPaneContext = 3
+
+// diffStyles holds the diff colors, built from the current theme.
+type diffStyles struct {
+	header  lipgloss.Style
+	hunk    lipgloss.Style
+	added   lipgloss.Style
+	removed lipgloss.Style
+	context lipgloss.Style
+}
+
+func newDiffStyles() diffStyles {
+	return diffStyles{
+		header: lipgloss.NewStyle().
+			Foreground(common.ColorMuted).
+			Bold(true),
+		hunk: lipgloss.NewStyle().
+			Foreground(common.ColorAccent),
+		added: lipgloss.NewStyle().
+			Foreground(common.ColorSuccess),
+		removed: lipgloss.NewStyle().
+			Foreground(common.ColorError),
+		context: lipgloss.NewStyle().
+			Foreground(common.ColorText),
+	}
+}
+
+// render colors a unified diff line by line, cutting lines longer than width
+// (0 for no limit). Tabs are expanded so the columns line up.
+func (s diffStyles) render(diff string, width int) []string {
+	if diff == "" {
+		return nil
+	}
+
+	lines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		if width > 0 {
+			line = runewidth.Truncate(line, width, "…")
+		}
+		out[i] = s.styleFor(line).Render(line)
+	}
+	return out
+}
+
+// styleFor returns the style of a diff line.
+func (s diffStyles) styleFor(line string) lipgloss.Style {
+	switch {
+	case strings.HasPrefix(line, "diff --git"), strings.HasPrefix(line, "index "),
+		strings.HasPrefix(line, "--- "), strings.HasPrefix(line, "+++ "),
+		strings.HasPrefix(line, "new file"), strings.HasPrefix(line, "deleted file"),
+		strings.HasPrefix(line, "similarity"), strings.HasPrefix(line, "rename "):
+		return s.header
+	case strings.HasPrefix(line, "@@"):
+		return s.hunk
+	case strings.HasPrefix(line, "+"):
+		return s.added
+	case strings.HasPrefix(line, "-"):
+		return s.removed
+	}
+	return s.context
+}
+
+// diffPane shows the staged diff one file at a time. It is hidden until
+// toggled, and the diff is loaded the first time it is shown.
+type diffPane struct {
+	files   []git.FileDiff
+	loaded  bool
+	err     error
+	visible bool
+	file    int // index of the file shown
+	offset  int // first visible line
+	width   int // text width inside the box
+	height  int // visible diff lines
+	styles  diffPaneStyles
+}
+
+// diffPaneStyles holds the diff pane styles, built from the current theme.
+type diffPaneStyles struct {
+	box   lipgloss.Style
+	title lipgloss.Style
+	muted lipgloss.Style
+	diff  diffStyles
+}
+
+func newDiffPane() diffPane {
+	return diffPane{
+		width:  60,
+		height: 10,
+		styles: diffPaneStyles{
+			box: lipgloss.NewStyle().
+				Border(lipgloss.RoundedBorder()).
+				BorderForeground(common.ColorBorder).
+				Padding(0, 1),
+			title: lipgloss.NewStyle().
+				Foreground(common.ColorPrimary).
+				Bold(true),
+			muted: lipgloss.NewStyle().
+				Foreground(common.ColorMuted),
+			diff: newDiffStyles(),
+		},
+	}
+}
+
+// setSize sets the text area size of the pane.
+func (p *diffPane) setSize(width, height int) {
+	p.width = max(20, width)
+	p.height = max(3, height)
+	p.scroll(0)
+}
+
+// toggle shows or hides the pane, loading the staged diff the first time.
+func (p *diffPane) toggle() {
+	p.visible = !p.visible
+	if p.visible && !p.loaded {
+		p.loaded = true
+		diff, err := git.GetStagedDiff(diffPaneContext)
+		p.err = err
+		p.files = git.SplitDiffByFile(diff)
+	}
+}
+
+// handleKey handles the pane keys: ctrl+g toggles it, and while it is shown
+// pgup/pgdown scroll and alt+n/alt+p switch files. It reports whether the key was used.
+func (p *diffPane) handleKey(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "ctrl+g":
+		p.toggle()
+		return true
+	}
+	if !p.visible {
+		return false
+	}
+
+	switch msg.String() {
+	case "pgdown":
+		p.scroll(p.height / 2)
+	case "pgup":
+		p.scroll(-p.height / 2)
+	case "alt+n":
+		p.selectFile(p.file + 1)
+	case "alt+p":
+		p.selectFile(p.file - 1)
+	default:
+		return false
+	}
+	return true
+}
+
+// selectFile shows the file at idx from its first line.
+func (p *diffPane) selectFile(idx int) {
+	if idx < 0 || idx >= len(p.files) {
+		return
+	}
+	p.file = idx
+	p.offset = 0
+}
+
+// lines returns the lines of the current file diff.
+func (p diffPane) lines() []string {
+	if p.file >= len(p.files) {
+		return nil
+	}
+	return strings.Split(p.files[p.file].Diff, "\n")
+}
+
+// scroll moves the diff by delta lines.
+func (p *diffPane) scroll(delta int) {
+	p.offset = max(0, min(p.offset+delta, len(p.lines())-p.height))
+}
+
+// View renders the pane, or nothing while it is hidden.
+func (p diffPane) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	var title string
+	var body []string
+	switch {
+	case p.err != nil:
+		title = p.styles.title.Render("Staged diff")
+		body = []string{p.styles.muted.Render(p.err.Error())}
+	case len(p.files) == 0:
+		title = p.styles.title.Render("Staged diff")
+		body = []string{p.styles.muted.Render("No staged changes")}
+	default:
+		lines := p.lines()
+		end := min(len(lines), p.offset+p.height)
+		title = p.styles.title.Render(fmt.Sprintf("Staged diff %d/%d", p.file+1, len(p.files))) + " " +
+			p.styles.muted.Render(runewidth.Truncate(p.files[p.file].Path, max(0, p.width-18), "…"))
+		body = p.styles.diff.render(strings.Join(lines[p.offset:end], "\n"), p.width)
+		if end < len(lines) {
+			body = append(body, p.styles.muted.Render(fmt.Sprintf("↓ %d more lines", len(lines)-end)))
+		}
+	}
+
+	for len(body) < p.height+1 {
+		body = append(body, "")
+	}
+	return p.styles.box.Width(p.width + 2).Render(title + "\n" + strings.Join(body, "\n"))
+}
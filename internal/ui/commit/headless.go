@@ -0,0 +1,81 @@
+package commit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mritd/gitflow-toolkit/v3/internal/git"
+)
+
+// Fields holds commit message fields given on the command line.
+type Fields struct {
+	Type    string
+	Scope   string
+	Subject string
+	Body    string
+	Footer  string
+}
+
+// BuildMessage validates the fields with the same rules as the interactive inputs
+// and builds the commit message, using the subject as body if none is given.
+func BuildMessage(f Fields) (git.CommitMessage, error) {
+	if f.Type == "" {
+		return git.CommitMessage{}, errors.New("Type cannot be empty")
+	}
+	if !isCommitType(f.Type) {
+		return git.CommitMessage{}, fmt.Errorf("unknown commit type %q", f.Type)
+	}
+	if err := validateScope(f.Scope); err != nil {
+		return git.CommitMessage{}, err
+	}
+	if err := validateSubject(f.Subject); err != nil {
+		return git.CommitMessage{}, err
+	}
+
+	body := strings.TrimSpace(f.Body)
+	if body == "" {
+		body = f.Subject
+	}
+
+	return git.CommitMessage{
+		Type:    f.Type,
+		Scope:   f.Scope,
+		Subject: f.Subject,
+		Body:    body,
+		Footer:  strings.TrimSpace(f.Footer),
+		SOB:     git.CreateSOB(),
+	}, nil
+}
+
+// RunHeadless commits the fields without any TUI, for scripts and other tools.
+// The lucky search runs without progress display and stops at the configured timeout.
+func RunHeadless(f Fields, luckyPrefix LuckyPrefixFunc) Result {
+	msg, err := BuildMessage(f)
+	if err != nil {
+		return Result{Err: err}
+	}
+
+	return commitWithLucky(msg, luckyPrefix, runLuckyHeadless)
+}
+
+// runLuckyHeadless runs the lucky search without progress display and records the outcome.
+func runLuckyHeadless(result Result, prefix string) Result {
+	opts := luckyOptions(prefix)
+
+	ctx := context.Background()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	err := opts.Search(ctx)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		result.LuckyTimedOut = opts.Timeout
+	} else if err != nil {
+		result.LuckyFailed = err
+	}
+	return result
+}
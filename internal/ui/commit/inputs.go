@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -18,94 +19,150 @@ import (
 	"github.com/mritd/gitflow-toolkit/v3/internal/ui/common"
 )
 
-// Spinner frames with colors
-var (
-	spinnerFrame1 = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("❯")
-	spinnerFrame2 = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("❯")
-	spinnerFrame3 = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render("❯")
-)
+// inputsStyles holds the inputs screen styles, built from the current theme.
+type inputsStyles struct {
+	titleLayout   lipgloss.Style
+	title         lipgloss.Style
+	blockLayout   lipgloss.Style
+	cursor        lipgloss.Style
+	promptFocus   lipgloss.Style
+	promptNormal  lipgloss.Style
+	textFocus     lipgloss.Style
+	textNormal    lipgloss.Style
+	placeholder   lipgloss.Style
+	buttonLayout  lipgloss.Style
+	buttonFocus   lipgloss.Style
+	buttonNormal  lipgloss.Style
+	err           lipgloss.Style
+	help          lipgloss.Style
+	lineLength    lipgloss.Style
+	lineTooLong   lipgloss.Style
+	areaLayout    lipgloss.Style
+	areaFocus     textarea.Style
+	areaNormal    textarea.Style
+	spinnerFrames [3]string // colored frames of the error spinner
+}
 
-// Input field styles
-var (
-	inputsTitleLayout = lipgloss.NewStyle().
-				Padding(1, 0, 1, 2)
-
-	inputsTitleStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.AdaptiveColor{Light: "#333333", Dark: "#DDDDDD"}).
-				Background(lipgloss.AdaptiveColor{Light: "#7653FF", Dark: "#7653FF"}).
-				Bold(true).
-				Padding(0, 1)
-
-	inputsBlockLayout = lipgloss.NewStyle().
-				Padding(0, 0, 0, 0)
-
-	inputsCursorStyle = lipgloss.NewStyle().
-				Foreground(common.ColorSuccess)
-
-	inputsPromptFocusStyle = lipgloss.NewStyle().
-				Border(lipgloss.NormalBorder(), false, false, false, true).
-				BorderForeground(common.ColorBorder).
-				Foreground(common.ColorPrimary).
-				Bold(true).
-				Padding(0, 0, 0, 1)
-
-	inputsPromptNormalStyle = lipgloss.NewStyle().
-				Foreground(common.ColorText).
-				Padding(0, 0, 0, 2)
-
-	inputsTextFocusStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.AdaptiveColor{Light: "#1A1A1A", Dark: "#FFFDF5"}).
-				Bold(true)
-
-	inputsTextNormalStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.AdaptiveColor{Light: "#1A1A1A", Dark: "#FFFDF5"})
-
-	inputsButtonLayout = lipgloss.NewStyle().
-				Padding(1, 0, 1, 2)
-
-	inputsButtonFocusStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.AdaptiveColor{Light: "#FFFDF5", Dark: "#FFFDF5"}).
-				Background(common.ColorSuccess).
-				Padding(0, 1).
-				Bold(true)
-
-	inputsButtonNormalStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.AdaptiveColor{Light: "#626262", Dark: "#DDDDDD"}).
-				Background(lipgloss.AdaptiveColor{Light: "#DDDDDD", Dark: "#626262"}).
-				Padding(0, 1)
-
-	inputsErrStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFDF5")).
-			Background(lipgloss.Color("#FF6037")).
+func newInputsStyles() inputsStyles {
+	frame := func(c lipgloss.TerminalColor) string {
+		return lipgloss.NewStyle().Foreground(c).Render("❯")
+	}
+	placeholder := lipgloss.NewStyle().
+		Foreground(common.ColorMuted)
+	textFocus := lipgloss.NewStyle().
+		Foreground(common.ColorEmphasis).
+		Bold(true)
+	textNormal := lipgloss.NewStyle().
+		Foreground(common.ColorEmphasis)
+
+	return inputsStyles{
+		titleLayout: lipgloss.NewStyle().
+			Padding(1, 0, 1, 2),
+		title: lipgloss.NewStyle().
+			Foreground(common.ColorTitleFg).
+			Background(common.ColorAccent).
+			Bold(true).
+			Padding(0, 1),
+		blockLayout: lipgloss.NewStyle().
+			Padding(0, 0, 0, 0),
+		cursor: lipgloss.NewStyle().
+			Foreground(common.ColorSuccess),
+		promptFocus: lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			BorderForeground(common.ColorBorder).
+			Foreground(common.ColorPrimary).
+			Bold(true).
+			Padding(0, 0, 0, 1),
+		promptNormal: lipgloss.NewStyle().
+			Foreground(common.ColorText).
+			Padding(0, 0, 0, 2),
+		textFocus:   textFocus,
+		textNormal:  textNormal,
+		placeholder: placeholder,
+		buttonLayout: lipgloss.NewStyle().
+			Padding(1, 0, 1, 2),
+		buttonFocus: lipgloss.NewStyle().
+			Foreground(common.ColorTitleFg).
+			Background(common.ColorSuccess).
+			Padding(0, 1).
+			Bold(true),
+		buttonNormal: lipgloss.NewStyle().
+			Foreground(common.ColorMuted).
+			Background(common.ColorButton).
+			Padding(0, 1),
+		err: lipgloss.NewStyle().
+			Foreground(common.ColorTitleFg).
+			Background(common.ColorAlert).
 			Padding(0, 1).
 			Bold(true).
-			MarginLeft(1)
-
-	inputsHelpStyle = lipgloss.NewStyle().
+			MarginLeft(1),
+		help: lipgloss.NewStyle().
 			Foreground(common.ColorMuted).
 			PaddingLeft(2).
-			PaddingTop(1)
+			PaddingTop(1),
+		lineLength: lipgloss.NewStyle().
+			Foreground(common.ColorMuted),
+		lineTooLong: lipgloss.NewStyle().
+			Foreground(common.ColorWarning).
+			Bold(true),
+		areaLayout: lipgloss.NewStyle().
+			PaddingLeft(2),
+		areaFocus: textarea.Style{
+			CursorLine:  textFocus,
+			Placeholder: placeholder,
+			Prompt:      lipgloss.NewStyle().Foreground(common.ColorBorder),
+			Text:        textFocus,
+		},
+		areaNormal: textarea.Style{
+			CursorLine:  textNormal,
+			Placeholder: placeholder,
+			Prompt:      lipgloss.NewStyle().Foreground(common.ColorButton),
+			Text:        textNormal,
+		},
+		spinnerFrames: [3]string{frame(common.ColorError), frame(common.ColorWarning), frame(common.ColorSuccess)},
+	}
+}
+
+// maxSubjectLen is the maximum commit subject length.
+const maxSubjectLen = 72
+
+const (
+	// bodyWrapWidth is the column at which body lines are wrapped.
+	bodyWrapWidth = 72
+	// bodyHeight is the number of visible body editor rows.
+	bodyHeight = 5
+	// bodyIndex is the index of the body field.
+	bodyIndex = 2
 )
 
 // inputField represents a single input field with validation.
+// Multi-line fields use a textarea below their label instead of a textinput.
 type inputField struct {
-	input   textinput.Model
-	checker func(s string) error
+	input     textinput.Model
+	area      textarea.Model
+	multiline bool
+	checker   func(s string) error
 }
 
-// inputsModel is the model for the inputs screen.
+// value returns the field content.
+func (f inputField) value() string {
+	if f.multiline {
+		return f.area.Value()
+	}
+	return f.input.Value()
+}
+
+// inputsModel is the message form of the commit composer. The composer handles
+// quitting, going back, submitting and opening the external editor.
 type inputsModel struct {
 	focusIndex int
 	title      string
 	inputs     []inputField
-	bodyText   string // store body separately since textinput is single-line
-	err        error
+	styles     inputsStyles
 	errSpinner spinner.Model
-	quitting   bool
-	submitted  bool
-	wantEditor bool // flag to open editor after exiting TUI
 	width      int  // terminal width
 	height     int  // terminal height
+	bodyEdited bool // body was written in the external editor and is kept as is
 }
 
 // inputsResult holds the result of the inputs screen.
@@ -120,9 +177,11 @@ func newInputsModel(commitType string) inputsModel {
 	m := inputsModel{
 		title:  "Commit Type: " + strings.ToUpper(commitType),
 		inputs: make([]inputField, 4),
+		styles: newInputsStyles(),
 	}
 
 	// Create error spinner with animated frames
+	spinnerFrame1, spinnerFrame2, spinnerFrame3 := m.styles.spinnerFrames[0], m.styles.spinnerFrames[1], m.styles.spinnerFrames[2]
 	m.errSpinner = spinner.New()
 	m.errSpinner.Spinner = spinner.Spinner{
 		Frames: []string{
@@ -144,32 +203,16 @@ func newInputsModel(commitType string) inputsModel {
 		{
 			prompt:      "1. SCOPE ",
 			placeholder: "Specifying place of the commit change (e.g., api, ui, core)",
-			checker: func(s string) error {
-				if strings.TrimSpace(s) == "" {
-					return errors.New("Scope cannot be empty")
-				}
-				if strings.ContainsAny(s, "():/\\") {
-					return errors.New("Scope cannot contain ():/\\")
-				}
-				return nil
-			},
+			checker:     validateScope,
 		},
 		{
 			prompt:      "2. SUBJECT ",
 			placeholder: "A short description, imperative mood, max 72 chars",
-			checker: func(s string) error {
-				if strings.TrimSpace(s) == "" {
-					return errors.New("Subject cannot be empty")
-				}
-				if len(s) > 72 {
-					return errors.New("Subject should be <= 72 chars")
-				}
-				return nil
-			},
+			checker:     validateSubject,
 		},
 		{
 			prompt:      "3. BODY ",
-			placeholder: "Detailed description (optional, Ctrl+E open editor)",
+			placeholder: "Detailed description, lists continue on enter (optional, Ctrl+E open editor)",
 			checker:     nil,
 		},
 		{
@@ -184,16 +227,16 @@ func newInputsModel(commitType string) inputsModel {
 		ti.Prompt = p.prompt
 		ti.Placeholder = p.placeholder
 		ti.CharLimit = 256
-		ti.Cursor.Style = inputsCursorStyle
-		ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(common.ColorMuted)
+		ti.Cursor.Style = m.styles.cursor
+		ti.PlaceholderStyle = m.styles.placeholder
 
 		if i == 0 {
-			ti.PromptStyle = inputsPromptFocusStyle
-			ti.TextStyle = inputsTextFocusStyle
+			ti.PromptStyle = m.styles.promptFocus
+			ti.TextStyle = m.styles.textFocus
 			ti.Focus()
 		} else {
-			ti.PromptStyle = inputsPromptNormalStyle
-			ti.TextStyle = inputsTextNormalStyle
+			ti.PromptStyle = m.styles.promptNormal
+			ti.TextStyle = m.styles.textNormal
 		}
 
 		m.inputs[i] = inputField{
@@ -202,9 +245,46 @@ func newInputsModel(commitType string) inputsModel {
 		}
 	}
 
+	// The body is edited in place with a multi-line textarea
+	ta := textarea.New()
+	ta.Placeholder = prompts[bodyIndex].placeholder
+	ta.Prompt = "│ "
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+	ta.FocusedStyle = m.styles.areaFocus
+	ta.BlurredStyle = m.styles.areaNormal
+	ta.Cursor.Style = m.styles.cursor
+	ta.SetHeight(bodyHeight)
+	ta.SetWidth(bodyWrapWidth + lipgloss.Width(ta.Prompt))
+	ta.Blur()
+	m.inputs[bodyIndex].area = ta
+	m.inputs[bodyIndex].multiline = true
+
 	return m
 }
 
+// validateScope checks the commit scope.
+func validateScope(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return errors.New("Scope cannot be empty")
+	}
+	if strings.ContainsAny(s, "():/\\") {
+		return errors.New("Scope cannot contain ():/\\")
+	}
+	return nil
+}
+
+// validateSubject checks the commit subject.
+func validateSubject(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return errors.New("Subject cannot be empty")
+	}
+	if len(s) > maxSubjectLen {
+		return fmt.Errorf("Subject should be <= %d chars", maxSubjectLen)
+	}
+	return nil
+}
+
 func (m inputsModel) Init() tea.Cmd {
 	return tea.Batch(textinput.Blink, m.errSpinner.Tick)
 }
@@ -222,6 +302,9 @@ func (m inputsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		for i := range m.inputs {
 			m.inputs[i].input.Width = inputWidth
 		}
+		// Wrap the body at 72 columns unless the terminal is narrower
+		area := &m.inputs[bodyIndex].area
+		area.SetWidth(min(bodyWrapWidth, msg.Width-6) + lipgloss.Width(area.Prompt))
 		return m, nil
 
 	case spinner.TickMsg:
@@ -231,47 +314,32 @@ func (m inputsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "ctrl+c", "esc":
-			m.quitting = true
-			return m, tea.Quit
-
-		case "ctrl+e":
-			// Open editor for body field (index 2)
-			if m.focusIndex == 2 {
-				m.wantEditor = true
-				return m, tea.Quit
-			}
-
 		case "enter":
 			if m.focusIndex == len(m.inputs) {
-				// On submit button - validate all and submit
-				for _, f := range m.inputs {
-					if f.checker != nil {
-						if err := f.checker(f.input.Value()); err != nil {
-							m.err = err
-							return m, nil
-						}
-					}
-				}
-				m.submitted = true
-				return m, tea.Quit
+				return m, nil
+			}
+			if m.focusIndex == bodyIndex {
+				return m, m.bodyNewline()
 			}
 			// Move to next field
-			fallthrough
+			return m.nextField()
+
+		case "tab":
+			return m.nextField()
+
+		case "shift+tab":
+			return m.prevField()
 
-		case "tab", "down":
-			m.focusIndex++
-			if m.focusIndex > len(m.inputs) {
-				m.focusIndex = 0
+		case "down":
+			// Inside the body, down moves between lines until the last row
+			if m.focusIndex != bodyIndex || m.bodyAtLastRow() {
+				return m.nextField()
 			}
-			return m, m.updateFocus()
 
-		case "shift+tab", "up":
-			m.focusIndex--
-			if m.focusIndex < 0 {
-				m.focusIndex = len(m.inputs)
+		case "up":
+			if m.focusIndex != bodyIndex || m.bodyAtFirstRow() {
+				return m.prevField()
 			}
-			return m, m.updateFocus()
 		}
 	}
 
@@ -279,17 +347,89 @@ func (m inputsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, m.updateInputs(msg)
 }
 
+// nextField moves the focus to the next field, wrapping around after Submit.
+func (m inputsModel) nextField() (tea.Model, tea.Cmd) {
+	m.focusIndex++
+	if m.focusIndex > len(m.inputs) {
+		m.focusIndex = 0
+	}
+	return m, m.updateFocus()
+}
+
+// prevField moves the focus to the previous field.
+func (m inputsModel) prevField() (tea.Model, tea.Cmd) {
+	m.focusIndex--
+	if m.focusIndex < 0 {
+		m.focusIndex = len(m.inputs)
+	}
+	return m, m.updateFocus()
+}
+
+// bodyAtFirstRow reports whether the body cursor is on its first visual row.
+func (m inputsModel) bodyAtFirstRow() bool {
+	area := m.inputs[bodyIndex].area
+	return area.Line() == 0 && area.LineInfo().RowOffset == 0
+}
+
+// bodyAtLastRow reports whether the body cursor is on its last visual row.
+func (m inputsModel) bodyAtLastRow() bool {
+	area := m.inputs[bodyIndex].area
+	info := area.LineInfo()
+	return area.Line() == area.LineCount()-1 && info.RowOffset >= info.Height-1
+}
+
+// bodyLine returns the body line under the cursor and the cursor column in it.
+func (m inputsModel) bodyLine() (string, int) {
+	area := m.inputs[bodyIndex].area
+	lines := strings.Split(area.Value(), "\n")
+	line := ""
+	if row := area.Line(); row < len(lines) {
+		line = lines[row]
+	}
+	info := area.LineInfo()
+	return line, info.StartColumn + info.ColumnOffset
+}
+
+// bodyNewline inserts a line break in the body, continuing a list item with the
+// next bullet. Enter on an empty list item removes its bullet and ends the list.
+func (m inputsModel) bodyNewline() tea.Cmd {
+	area := &m.inputs[bodyIndex].area
+	line, col := m.bodyLine()
+	marker := listMarker(line)
+	if marker == "" || col != len([]rune(line)) {
+		area.InsertString("\n")
+		return nil
+	}
+
+	if strings.TrimSpace(line) == strings.TrimSpace(marker) {
+		for range []rune(line) {
+			*area, _ = area.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+		}
+		return nil
+	}
+	area.InsertString("\n" + nextListMarker(marker))
+	return nil
+}
+
 func (m inputsModel) updateFocus() tea.Cmd {
 	cmds := make([]tea.Cmd, len(m.inputs))
 	for i := range m.inputs {
+		if m.inputs[i].multiline {
+			if i == m.focusIndex {
+				cmds[i] = m.inputs[i].area.Focus()
+			} else {
+				m.inputs[i].area.Blur()
+			}
+			continue
+		}
 		if i == m.focusIndex {
 			cmds[i] = m.inputs[i].input.Focus()
-			m.inputs[i].input.PromptStyle = inputsPromptFocusStyle
-			m.inputs[i].input.TextStyle = inputsTextFocusStyle
+			m.inputs[i].input.PromptStyle = m.styles.promptFocus
+			m.inputs[i].input.TextStyle = m.styles.textFocus
 		} else {
 			m.inputs[i].input.Blur()
-			m.inputs[i].input.PromptStyle = inputsPromptNormalStyle
-			m.inputs[i].input.TextStyle = inputsTextNormalStyle
+			m.inputs[i].input.PromptStyle = m.styles.promptNormal
+			m.inputs[i].input.TextStyle = m.styles.textNormal
 		}
 	}
 	return tea.Batch(cmds...)
@@ -298,16 +438,93 @@ func (m inputsModel) updateFocus() tea.Cmd {
 func (m inputsModel) updateInputs(msg tea.Msg) tea.Cmd {
 	cmds := make([]tea.Cmd, len(m.inputs))
 	for i := range m.inputs {
+		if m.inputs[i].multiline {
+			m.inputs[i].area, cmds[i] = m.inputs[i].area.Update(msg)
+			continue
+		}
 		m.inputs[i].input, cmds[i] = m.inputs[i].input.Update(msg)
 	}
 	return tea.Batch(cmds...)
 }
 
-func (m inputsModel) View() string {
-	if m.quitting || m.submitted || m.wantEditor {
-		return ""
+// fieldView renders a field; the body shows its label with the length of the
+// current line above the textarea.
+func (m inputsModel) fieldView(i int) string {
+	f := m.inputs[i]
+	if !f.multiline {
+		return f.input.View()
 	}
 
+	focused := i == m.focusIndex
+	label := m.styles.promptNormal.Render(f.input.Prompt)
+	if focused {
+		label = m.styles.promptFocus.Render(f.input.Prompt)
+		line, _ := m.bodyLine()
+		width := lipgloss.Width(line)
+		indicator := m.styles.lineLength
+		if width > bodyWrapWidth {
+			indicator = m.styles.lineTooLong
+		}
+		label += indicator.Render(fmt.Sprintf("Ln %d • %d/%d", f.area.Line()+1, width, bodyWrapWidth))
+	}
+	return label + "\n" + m.styles.areaLayout.Render(f.area.View())
+}
+
+// fieldHeight returns the number of lines a field takes.
+func (m inputsModel) fieldHeight(i int) int {
+	if m.inputs[i].multiline {
+		return 1 + m.inputs[i].area.Height()
+	}
+	return 1
+}
+
+// visibleFields returns the range of fields that fit in the available height,
+// keeping the focused field (or the last one when Submit is focused) visible.
+func visibleFields(heights []int, focus, available int) (int, int) {
+	total := 0
+	for _, h := range heights {
+		total += h
+	}
+	if total <= available || len(heights) == 0 {
+		return 0, len(heights)
+	}
+
+	if focus >= len(heights) {
+		focus = len(heights) - 1
+	}
+	start, end := focus, focus+1
+	used := heights[focus]
+	for {
+		grown := false
+		if end < len(heights) && used+heights[end] <= available {
+			used += heights[end]
+			end++
+			grown = true
+		}
+		if start > 0 && used+heights[start-1] <= available {
+			start--
+			used += heights[start]
+			grown = true
+		}
+		if !grown {
+			return start, end
+		}
+	}
+}
+
+// validate checks all fields and returns the first error.
+func (m inputsModel) validate() error {
+	for _, f := range m.inputs {
+		if f.checker != nil {
+			if err := f.checker(f.value()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m inputsModel) View() string {
 	var b strings.Builder
 
 	// Calculate available height for inputs
@@ -320,140 +537,62 @@ func (m inputsModel) View() string {
 	}
 
 	// Determine which inputs to show based on focus and available height
-	startIdx := 0
-	endIdx := len(m.inputs)
-
-	if availableHeight < len(m.inputs) {
-		// Need to scroll - keep focused item visible
-		if m.focusIndex < len(m.inputs) {
-			// Center the focused input if possible
-			startIdx = m.focusIndex - availableHeight/2
-			if startIdx < 0 {
-				startIdx = 0
-			}
-			endIdx = startIdx + availableHeight
-			if endIdx > len(m.inputs) {
-				endIdx = len(m.inputs)
-				startIdx = endIdx - availableHeight
-				if startIdx < 0 {
-					startIdx = 0
-				}
-			}
-		}
+	heights := make([]int, len(m.inputs))
+	for i := range m.inputs {
+		heights[i] = m.fieldHeight(i)
 	}
+	startIdx, endIdx := visibleFields(heights, m.focusIndex, availableHeight)
 
 	// Show scroll indicator if needed
 	if startIdx > 0 {
-		b.WriteString(inputsHelpStyle.Render("  ↑ more above"))
+		b.WriteString(m.styles.help.Render("  ↑ more above"))
 		b.WriteRune('\n')
 	}
 
 	// Input fields
 	for i := startIdx; i < endIdx; i++ {
-		b.WriteString(m.inputs[i].input.View())
-		// Show indicator if body has multi-line content from editor
-		if i == 2 && m.bodyText != "" && strings.Contains(m.bodyText, "\n") {
-			lineCount := strings.Count(m.bodyText, "\n") + 1
-			indicator := lipgloss.NewStyle().Foreground(common.ColorSuccess).Render(
-				fmt.Sprintf(" (%d lines)", lineCount))
-			b.WriteString(indicator)
-		}
+		b.WriteString(m.fieldView(i))
 		b.WriteRune('\n')
 	}
 
 	// Show scroll indicator if needed
 	if endIdx < len(m.inputs) {
-		b.WriteString(inputsHelpStyle.Render("  ↓ more below"))
+		b.WriteString(m.styles.help.Render("  ↓ more below"))
 		b.WriteRune('\n')
 	}
 
 	// Submit button
-	button := inputsButtonNormalStyle.Render("Submit")
+	button := m.styles.buttonNormal.Render("Submit")
 	if m.focusIndex == len(m.inputs) {
-		button = inputsButtonFocusStyle.Render("Submit")
+		button = m.styles.buttonFocus.Render("Submit")
 	}
 
 	// Validate and show error with animated spinner
-	m.err = nil
-	for _, f := range m.inputs {
-		if f.checker != nil {
-			if err := f.checker(f.input.Value()); err != nil {
-				m.err = err
-				break
-			}
-		}
-	}
-
-	if m.err != nil {
-		button += " " + m.errSpinner.View() + inputsErrStyle.Render(m.err.Error())
+	if err := m.validate(); err != nil {
+		button += " " + m.errSpinner.View() + m.styles.err.Render(err.Error())
 	}
 
-	b.WriteString(inputsButtonLayout.Render(button))
+	b.WriteString(m.styles.buttonLayout.Render(button))
 
 	// Help text
-	b.WriteString(inputsHelpStyle.Render("↑/↓ navigate • enter next/submit • ctrl+c quit • ctrl+e editor (body)"))
+	b.WriteString(m.styles.help.Render("tab/↑/↓ navigate • enter next/submit • ctrl+e editor • esc back • ctrl+c quit"))
 
-	title := inputsTitleLayout.Render(inputsTitleStyle.Render(m.title))
-	inputs := inputsBlockLayout.Render(b.String())
+	title := m.styles.titleLayout.Render(m.styles.title.Render(m.title))
+	inputs := m.styles.blockLayout.Render(b.String())
 
 	return lipgloss.JoinVertical(lipgloss.Left, title, inputs)
 }
 
 func (m inputsModel) result() inputsResult {
-	// Use bodyText if it has content (from editor), otherwise use input field
-	body := m.bodyText
-	if body == "" {
-		body = m.inputs[2].input.Value()
+	body := strings.TrimSpace(m.inputs[bodyIndex].value())
+	if !m.bodyEdited {
+		body = wrapBody(body, bodyWrapWidth)
 	}
 	return inputsResult{
-		scope:   strings.TrimSpace(m.inputs[0].input.Value()),
-		subject: strings.TrimSpace(m.inputs[1].input.Value()),
-		body:    strings.TrimSpace(body),
-		footer:  strings.TrimSpace(m.inputs[3].input.Value()),
-	}
-}
-
-// runInputs runs the inputs screen and returns the result.
-func runInputs(commitType string) (inputsResult, error) {
-	m := newInputsModel(commitType)
-
-	for {
-		p := tea.NewProgram(m)
-		finalModel, err := p.Run()
-		if err != nil {
-			return inputsResult{}, err
-		}
-
-		m = finalModel.(inputsModel)
-
-		if m.quitting {
-			return inputsResult{}, errUserAborted
-		}
-
-		if m.submitted {
-			return m.result(), nil
-		}
-
-		if m.wantEditor {
-			// Run editor outside of TUI
-			// Use existing bodyText or input field value as initial content
-			initialContent := m.bodyText
-			if initialContent == "" {
-				initialContent = m.inputs[2].input.Value()
-			}
-			content, err := runExternalEditor(initialContent)
-			if err == nil {
-				m.bodyText = content
-				// Show truncated preview in input field
-				preview := strings.ReplaceAll(content, "\n", " ")
-				if len(preview) > 50 {
-					preview = preview[:50] + "..."
-				}
-				m.inputs[2].input.SetValue(preview)
-			}
-			m.wantEditor = false
-			// Continue the loop to restart TUI
-		}
+		scope:   strings.TrimSpace(m.inputs[0].value()),
+		subject: strings.TrimSpace(m.inputs[1].value()),
+		body:    body,
+		footer:  strings.TrimSpace(m.inputs[3].value()),
 	}
 }
 
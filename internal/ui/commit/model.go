@@ -2,29 +2,42 @@
 package commit
 
 import (
-	"errors"
+	"fmt"
+	"os"
+	"time"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/mritd/gitflow-toolkit/v3/config"
+	"github.com/mritd/gitflow-toolkit/v3/consts"
 	"github.com/mritd/gitflow-toolkit/v3/internal/git"
 	"github.com/mritd/gitflow-toolkit/v3/internal/ui/common"
 )
 
 // Result represents the result of the commit flow.
 type Result struct {
-	Cancelled    bool
-	Err          error
-	Message      git.CommitMessage
-	LuckySkipped bool   // true if lucky commit was skipped (Ctrl+C)
-	LuckyFailed  error  // error if lucky commit failed
-	Hash         string // final commit hash (may be lucky hash)
+	Cancelled     bool
+	Err           error
+	Message       git.CommitMessage
+	LuckySkipped  bool          // true if lucky commit was skipped (Ctrl+C)
+	LuckyFailed   error         // error if lucky commit failed
+	LuckyTimedOut time.Duration // lucky commit timeout that was hit, 0 if none
+	Hash          string        // final commit hash (may be lucky hash)
+}
+
+// LuckyPrefixFunc returns the validated lucky commit prefix for a commit message.
+type LuckyPrefixFunc func(msg git.CommitMessage) (string, error)
+
+// Options configures the commit flow.
+type Options struct {
+	NoCache bool // do not use cached AI file summaries
+	Review  bool // review staged changes with AI before generating the message
+	Plain   bool // use line-oriented prompts instead of the TUI
 }
 
 // Run runs the interactive commit flow.
-// The luckyPrefix parameter is the validated lucky commit prefix (empty if not enabled).
-func Run(luckyPrefix string) Result {
+// The luckyPrefix parameter resolves the lucky commit prefix for the message (nil if not enabled).
+func Run(luckyPrefix LuckyPrefixFunc, opts Options) Result {
 	var result Result
 
 	// Detect commit type from branch name if enabled
@@ -35,99 +48,65 @@ func Run(luckyPrefix string) Result {
 		}
 	}
 
-	// Step 1: Select commit type or AI generate
-	choice, err := runSelector(initialType)
-	if err != nil {
-		if errors.Is(err, errUserAborted) {
-			result.Cancelled = true
-			return result
-		}
-		result.Err = err
-		return result
-	}
-
-	// Check if user selected AI generate
-	if choice == aiGenerateChoice {
-		return runAIFlow(luckyPrefix)
+	if opts.Plain {
+		return runPlainFlow(common.NewPrompter(os.Stdin, os.Stdout), initialType, luckyPrefix)
 	}
 
-	// Original flow for manual commit type selection
-	return runManualFlow(choice, luckyPrefix)
-}
-
-// runManualFlow runs the manual commit flow with the given commit type.
-func runManualFlow(commitType, luckyPrefix string) Result {
-	var result Result
-
-	// Step 2: Input all fields (scope, subject, body, footer)
-	inputs, err := runInputs(commitType)
+	// Compose the message on a single screen, or switch to AI generation
+	m, err := runComposer(initialType)
 	if err != nil {
-		if errors.Is(err, errUserAborted) {
-			result.Cancelled = true
-			return result
-		}
 		result.Err = err
 		return result
 	}
-
-	// Create SOB
-	sob := git.CreateSOB()
-
-	// If body is empty, use subject as body
-	body := inputs.body
-	if body == "" {
-		body = inputs.subject
-	}
-
-	// Build commit message
-	result.Message = git.CommitMessage{
-		Type:    commitType,
-		Scope:   inputs.scope,
-		Subject: inputs.subject,
-		Body:    body,
-		Footer:  inputs.footer,
-		SOB:     sob,
-	}
-
-	// Step 3: Confirm and commit
-	confirmed, err := confirmCommit(result.Message)
-	if err != nil {
-		if errors.Is(err, errUserAborted) {
-			result.Cancelled = true
-			return result
-		}
-		result.Err = err
-		return result
-	}
-
-	if !confirmed {
+	if m.cancelled {
 		result.Cancelled = true
 		return result
 	}
+	if m.choice == aiGenerateChoice {
+		return runAIFlow(luckyPrefix, opts)
+	}
 
-	// Perform commit and handle lucky commit
-	return performCommit(result.Message, luckyPrefix)
+	return performCommit(m.message, luckyPrefix)
 }
 
 // runAIFlow runs the AI-powered commit flow.
-func runAIFlow(luckyPrefix string) Result {
-	var currentMessage string
+func runAIFlow(luckyPrefix LuckyPrefixFunc, opts Options) Result {
+	var candidates []string
+	var strategy string
+	var last *aiModel    // previous run, reused so Retry skips file analysis
+	var previewErr error // shown in the preview, e.g. a failed refinement
+
+	abort, err := runReviewStep(opts)
+	if err != nil {
+		return Result{Err: err}
+	}
+	if abort {
+		return Result{Cancelled: true}
+	}
 
 	for {
 		// Run AI generation (only if we don't have a message yet, or user requested retry)
-		if currentMessage == "" {
-			aiResult := runAIGenerate()
+		if len(candidates) == 0 {
+			var aiResult aiResult
+			if last == nil {
+				aiResult = runAIGenerate(opts.NoCache)
+			} else {
+				aiResult = runAIRegenerate(*last)
+			}
 			if aiResult.Cancelled {
 				return Result{Cancelled: true}
 			}
 			if aiResult.Err != nil {
 				return Result{Err: aiResult.Err}
 			}
-			currentMessage = aiResult.Message
+			candidates = aiResult.Messages
+			strategy = aiResult.Strategy
+			last = aiResult.state
 		}
 
 		// Show preview
-		previewResult := runAIPreview(currentMessage)
+		previewResult := runAIPreview(candidates, strategy, previewErr)
+		previewErr = nil
 
 		switch previewResult.Action {
 		case "commit":
@@ -141,12 +120,23 @@ func runAIFlow(luckyPrefix string) Result {
 			if err != nil {
 				return Result{Err: err}
 			}
-			currentMessage = edited
+			candidates = []string{edited}
 			// Loop continues to show preview with edited message
 
 		case "retry":
-			// Clear message and regenerate
-			currentMessage = ""
+			// Clear messages and regenerate the final message only
+			candidates = nil
+
+		case "refine":
+			// Revise the selected message with a follow-up turn, keep it if cancelled
+			// On failure the candidates stay, so the user can retry, edit or commit
+			refined := runAIRefine(*last, previewResult.Message, previewResult.Instruction)
+			if refined.Err != nil {
+				previewErr = fmt.Errorf("refinement failed: %w", refined.Err)
+			} else if !refined.Cancelled {
+				candidates = refined.Messages
+				last = refined.state
+			}
 			// Loop continues
 
 		case "cancel":
@@ -273,7 +263,12 @@ func trimLeft(s string) string {
 }
 
 // performCommit commits the message and handles lucky commit.
-func performCommit(msg git.CommitMessage, luckyPrefix string) Result {
+func performCommit(msg git.CommitMessage, luckyPrefix LuckyPrefixFunc) Result {
+	return commitWithLucky(msg, luckyPrefix, runLucky)
+}
+
+// commitWithLucky commits the message, then runs lucky if luckyPrefix is set.
+func commitWithLucky(msg git.CommitMessage, luckyPrefix LuckyPrefixFunc, lucky func(Result, string) Result) Result {
 	var result Result
 	result.Message = msg
 
@@ -283,17 +278,14 @@ func performCommit(msg git.CommitMessage, luckyPrefix string) Result {
 		return result
 	}
 
-	// Run lucky commit if prefix is set
-	if luckyPrefix != "" {
-		cmd := git.LuckyCommitCmd(luckyPrefix)
-		luckyResult := common.RunLuckyCommit(luckyPrefix, cmd, git.GetHeadHash)
-
-		if luckyResult.Cancelled {
-			result.LuckySkipped = true
-		} else if luckyResult.Err != nil {
-			result.LuckyFailed = luckyResult.Err
+	// Run lucky commit if enabled; the prefix may depend on the message
+	if luckyPrefix != nil {
+		prefix, err := luckyPrefix(msg)
+		if err != nil {
+			result.LuckyFailed = err
+		} else {
+			result = lucky(result, prefix)
 		}
-		result.Hash = luckyResult.Hash
 	}
 
 	// Get hash if not already set
@@ -304,119 +296,38 @@ func performCommit(msg git.CommitMessage, luckyPrefix string) Result {
 	return result
 }
 
-// confirmCommit shows a preview and asks for confirmation using a TUI.
-func confirmCommit(msg git.CommitMessage) (bool, error) {
-	m := newConfirmModel(msg)
-	p := tea.NewProgram(m)
-
-	finalModel, err := p.Run()
-	if err != nil {
-		return false, err
-	}
-
-	result := finalModel.(confirmModel)
-	if result.cancelled {
-		return false, errUserAborted
+// luckyOptions returns the lucky search options for prefix using the configured engine.
+func luckyOptions(prefix string) common.LuckyOptions {
+	opts := common.LuckyOptions{
+		Prefix:   prefix,
+		Search:   git.ExternalLuckySearch(prefix),
+		Timeout:  git.GetLuckyTimeout(),
+		GetHash:  git.GetHeadHash,
+		HashRate: git.MeasureHashRate,
+		Expected: git.LuckyExpectedAttempts,
 	}
-
-	return result.confirmed, nil
-}
-
-// confirmModel is the bubbletea model for commit confirmation.
-type confirmModel struct {
-	msg       git.CommitMessage
-	confirmed bool
-	cancelled bool
-	selected  int // 0 = Commit, 1 = Cancel
-}
-
-func newConfirmModel(msg git.CommitMessage) confirmModel {
-	return confirmModel{msg: msg, selected: 0}
-}
-
-func (m confirmModel) Init() tea.Cmd {
-	return nil
-}
-
-func (m confirmModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "esc", "n":
-			m.cancelled = true
-			return m, tea.Quit
-		case "enter":
-			if m.selected == 0 {
-				m.confirmed = true
-			} else {
-				m.cancelled = true
-			}
-			return m, tea.Quit
-		case "y":
-			m.confirmed = true
-			return m, tea.Quit
-		case "left", "h":
-			m.selected = 0
-		case "right", "l":
-			m.selected = 1
-		case "tab":
-			m.selected = (m.selected + 1) % 2
-		}
+	if git.GetLuckyEngine() == consts.LuckyEngineBuiltin {
+		search := git.NewLuckySearch(prefix)
+		opts.Search = search.Run
+		opts.Attempts = search.Attempts
 	}
-	return m, nil
+	return opts
 }
 
-func (m confirmModel) View() string {
-	if m.confirmed || m.cancelled {
-		return ""
+// runLucky searches for a commit hash starting with prefix and records the outcome.
+func runLucky(result Result, prefix string) Result {
+	opts := luckyOptions(prefix)
+	luckyResult := common.RunLuckyCommit(opts)
+
+	if luckyResult.Cancelled {
+		result.LuckySkipped = true
+	} else if luckyResult.TimedOut {
+		result.LuckyTimedOut = opts.Timeout
+	} else if luckyResult.Err != nil {
+		result.LuckyFailed = luckyResult.Err
 	}
-
-	// Use same layout structure as inputs.go
-	titleLayout := lipgloss.NewStyle().Padding(1, 0, 1, 2)
-	titleStyle := lipgloss.NewStyle().
-		Foreground(common.ColorTitleFg).
-		Background(common.ColorTitleBg).
-		Bold(true).
-		Padding(0, 1)
-
-	// Content with left border (same as Result)
-	contentLayout := lipgloss.NewStyle().PaddingLeft(2)
-	contentStyle := lipgloss.NewStyle().
-		Border(lipgloss.NormalBorder(), false, false, false, true).
-		BorderForeground(common.ColorSuccess).
-		PaddingLeft(1)
-
-	// Buttons without border
-	buttonLayout := lipgloss.NewStyle().PaddingLeft(2).PaddingTop(1)
-
-	helpStyle := lipgloss.NewStyle().
-		Foreground(common.ColorMuted).
-		PaddingLeft(2).
-		PaddingTop(1)
-
-	// Commit message preview with border
-	preview := contentLayout.Render(contentStyle.Render(renderPreview(m.msg)))
-
-	// Buttons
-	buttons := buttonLayout.Render(renderButtons(m.selected))
-
-	title := titleLayout.Render(titleStyle.Render("Commit Preview"))
-	help := helpStyle.Render("y/enter confirm • n/esc cancel • ←/→ select")
-
-	return lipgloss.JoinVertical(lipgloss.Left, title, preview, buttons, help) + "\n"
-}
-
-// renderPreview renders the commit message preview using the same colors as result.
-func renderPreview(msg git.CommitMessage) string {
-	// Preview doesn't need wrapping (displayed in content box with its own width)
-	return common.FormatCommitMessage(common.CommitMessageContent{
-		Type:    msg.Type,
-		Scope:   msg.Scope,
-		Subject: msg.Subject,
-		Body:    msg.Body,
-		Footer:  msg.Footer,
-		SOB:     msg.SOB,
-	}, 0)
+	result.Hash = luckyResult.Hash
+	return result
 }
 
 // renderButtons renders the confirm/cancel buttons.
@@ -429,7 +340,7 @@ func renderButtons(selected int) string {
 
 	inactiveStyle := lipgloss.NewStyle().
 		Foreground(common.ColorMuted).
-		Background(lipgloss.Color("#3a3a3a")).
+		Background(common.ColorButton).
 		Padding(0, 2)
 
 	cancelActiveStyle := lipgloss.NewStyle().
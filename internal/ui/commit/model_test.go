@@ -1,75 +1,23 @@
 package commit
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/mritd/gitflow-toolkit/v3/consts"
 	"github.com/mritd/gitflow-toolkit/v3/internal/git"
+	"github.com/mritd/gitflow-toolkit/v3/internal/llm"
 	"github.com/mritd/gitflow-toolkit/v3/internal/ui/common"
 )
 
-func TestRenderPreview(t *testing.T) {
-	tests := []struct {
-		name     string
-		msg      git.CommitMessage
-		contains []string
-	}{
-		{
-			name: "basic message",
-			msg: git.CommitMessage{
-				Type:    "feat",
-				Scope:   "api",
-				Subject: "add new endpoint",
-			},
-			contains: []string{"feat", "api", "add new endpoint"},
-		},
-		{
-			name: "message with body",
-			msg: git.CommitMessage{
-				Type:    "fix",
-				Scope:   "auth",
-				Subject: "fix login bug",
-				Body:    "This fixes the login issue.",
-			},
-			contains: []string{"fix", "auth", "fix login bug", "This fixes the login issue"},
-		},
-		{
-			name: "message with footer",
-			msg: git.CommitMessage{
-				Type:    "feat",
-				Scope:   "core",
-				Subject: "add feature",
-				Footer:  "BREAKING CHANGE: API changed",
-			},
-			contains: []string{"feat", "core", "add feature", "BREAKING CHANGE"},
-		},
-		{
-			name: "full message",
-			msg: git.CommitMessage{
-				Type:    "feat",
-				Scope:   "api",
-				Subject: "add user endpoint",
-				Body:    "Add new user management endpoint.",
-				Footer:  "Closes #123",
-				SOB:     "Signed-off-by: Test <test@example.com>",
-			},
-			contains: []string{"feat", "api", "add user endpoint", "Add new user management", "Closes #123", "Signed-off-by"},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := renderPreview(tt.msg)
-
-			for _, s := range tt.contains {
-				if !strings.Contains(result, s) {
-					t.Errorf("renderPreview() should contain %q, got:\n%s", s, result)
-				}
-			}
-		})
-	}
-}
-
 func TestNewInputsModel(t *testing.T) {
 	m := newInputsModel("feat")
 
@@ -85,6 +33,10 @@ func TestNewInputsModel(t *testing.T) {
 	if !m.inputs[0].input.Focused() {
 		t.Error("first input should be focused")
 	}
+
+	if !m.inputs[bodyIndex].multiline {
+		t.Error("body should be a multi-line field")
+	}
 }
 
 func TestNewSelectorModel(t *testing.T) {
@@ -375,3 +327,1246 @@ func TestCalcVisibleRange(t *testing.T) {
 		})
 	}
 }
+
+func TestFileSkipReason(t *testing.T) {
+	tests := []struct {
+		name string
+		file git.FileDiff
+		want string
+	}{
+		{"regular file", git.FileDiff{Path: "main.go", Diff: "+package main"}, ""},
+		{"go.sum", git.FileDiff{Path: "go.sum", Diff: "+github.com/x v1.0.0 h1:abc"}, skipReasonLock},
+		{"nested lock file", git.FileDiff{Path: "web/package-lock.json", Diff: "+{}"}, skipReasonLock},
+		{"protobuf", git.FileDiff{Path: "api/v1/user.pb.go", Diff: "+package v1"}, skipReasonGenerated},
+		{"minified js", git.FileDiff{Path: "static/app.min.js", Diff: "+var a"}, skipReasonGenerated},
+		{
+			"generated marker",
+			git.FileDiff{Path: "mock.go", Diff: "@@ -0,0 +1,2 @@\n+// Code generated by mockgen. DO NOT EDIT.\n+package mock"},
+			skipReasonGenerated,
+		},
+		{"binary", git.FileDiff{Path: "logo.png", Diff: "Binary files a/logo.png and b/logo.png differ"}, skipReasonBinary},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fileSkipReason(tt.file); got != tt.want {
+				t.Errorf("fileSkipReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitFileDiff(t *testing.T) {
+	header := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go"
+	hunk := "@@ -1,1 +1,1 @@\n" + strings.Repeat("+0123456789abcdef\n", 10) + "+end"
+	diff := header + "\n" + hunk + "\n" + hunk + "\n" + hunk
+
+	t.Run("fits in budget", func(t *testing.T) {
+		chunks := splitFileDiff(diff, 10000)
+		if len(chunks) != 1 || chunks[0] != diff {
+			t.Errorf("splitFileDiff() = %d chunks, want original diff", len(chunks))
+		}
+	})
+
+	t.Run("split by hunks", func(t *testing.T) {
+		chunks := splitFileDiff(diff, 70)
+		if len(chunks) != 3 {
+			t.Fatalf("splitFileDiff() = %d chunks, want 3", len(chunks))
+		}
+		for i, chunk := range chunks {
+			if !strings.HasPrefix(chunk, header+"\n@@") {
+				t.Errorf("chunk %d should start with file header and hunk", i)
+			}
+		}
+	})
+
+	t.Run("truncate oversized hunk", func(t *testing.T) {
+		chunks := splitFileDiff(diff, 30)
+		if len(chunks) != 3 {
+			t.Fatalf("splitFileDiff() = %d chunks, want 3", len(chunks))
+		}
+		if !strings.Contains(chunks[0], "more lines truncated") {
+			t.Errorf("chunk should be truncated, got:\n%s", chunks[0])
+		}
+	})
+}
+
+func TestCountChangedLines(t *testing.T) {
+	diff := "diff --git a/a.sql b/a.sql\n--- a/a.sql\n+++ b/a.sql\n@@ -1,2 +1,2 @@\n--- old comment\n+++ new comment\n-x\n+y\n" +
+		"diff --git a/b.c b/b.c\n--- a/b.c\n+++ b/b.c\n@@ -1 +1 @@\n-i++;\n+++i;"
+	added, removed := countChangedLines(diff)
+	if added != 3 || removed != 3 {
+		t.Errorf("countChangedLines() = +%d/-%d, want +3/-3", added, removed)
+	}
+}
+
+func TestSkippedSummary(t *testing.T) {
+	file := git.FileDiff{Path: "go.sum", Diff: "--- a/go.sum\n+++ b/go.sum\n+a\n+b\n-c"}
+	want := "updated lock file (+2/-1 lines, not analyzed)"
+	if got := skippedSummary(file, skipReasonLock); got != want {
+		t.Errorf("skippedSummary() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildCommitPromptIgnoredFiles(t *testing.T) {
+	m := aiModel{
+		files:     []git.FileDiff{{Path: "main.go"}},
+		summaries: []string{"add entry point"},
+		ignored:   []string{"go.sum", "vendor/x/y.go"},
+		client:    &llm.Client{},
+	}
+
+	prompt := m.buildCommitPrompt(false)
+	if !strings.Contains(prompt, "- main.go: add entry point\n") {
+		t.Errorf("prompt should contain file summary, got:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "- also changed: go.sum, vendor/x/y.go\n") {
+		t.Errorf("prompt should list ignored files, got:\n%s", prompt)
+	}
+}
+
+func TestNewAIModelAllIgnored(t *testing.T) {
+	m := newAIModel(aiInput{ignored: []string{"go.sum", "vendor/x.go"}}, &llm.Client{})
+	if m.phase != "generating" || m.strategy != strategyPerFile {
+		t.Errorf("phase = %q, strategy = %q, want generating, %s", m.phase, m.strategy, strategyPerFile)
+	}
+
+	prompt := m.finalPrompt()
+	if !strings.Contains(prompt, "- also changed: go.sum, vendor/x.go\n") {
+		t.Errorf("prompt should list ignored files, got:\n%s", prompt)
+	}
+}
+
+func TestBuildSinglePassPrompt(t *testing.T) {
+	m := aiModel{
+		files: []git.FileDiff{
+			{Path: "main.go", Diff: "diff --git a/main.go b/main.go\n+package main"},
+			{Path: "go.sum", Diff: "diff --git a/go.sum b/go.sum\n+x h1:abc"},
+		},
+		summaries:  []string{"", "updated lock file (+1/-0 lines, not analyzed)"},
+		fileStatus: []int{1, 3},
+		ignored:    []string{"vendor/x.go"},
+		strategy:   strategySinglePass,
+	}
+
+	prompt := m.buildSinglePassPrompt()
+	if !strings.Contains(prompt, "+package main") {
+		t.Errorf("prompt should contain analyzable diff, got:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "h1:abc") {
+		t.Errorf("prompt should not contain skipped diff, got:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "- go.sum: updated lock file") {
+		t.Errorf("prompt should summarize skipped file, got:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "- also changed: vendor/x.go") {
+		t.Errorf("prompt should list ignored files, got:\n%s", prompt)
+	}
+}
+
+func TestAnalyzableTokens(t *testing.T) {
+	files := []git.FileDiff{
+		{Path: "main.go", Diff: strings.Repeat("a", 40)},
+		{Path: "go.sum", Diff: strings.Repeat("b", 400)},
+	}
+	plans := []filePlan{{chunks: []string{files[0].Diff}}, {skipReason: skipReasonLock}}
+
+	if got := analyzableTokens(files, plans); got != 10 {
+		t.Errorf("analyzableTokens() = %d, want 10", got)
+	}
+}
+
+func TestWriteHistoryContext(t *testing.T) {
+	t.Run("empty history", func(t *testing.T) {
+		var sb strings.Builder
+		aiModel{}.writeHistoryContext(&sb)
+		if sb.Len() != 0 {
+			t.Errorf("writeHistoryContext() = %q, want empty", sb.String())
+		}
+	})
+
+	t.Run("branch and commits", func(t *testing.T) {
+		m := aiModel{history: branchHistory{
+			branch:     "feat/login",
+			branchType: "feat",
+			commits:    []string{"feat(auth): add login form", "test(auth): cover login form"},
+		}}
+
+		var sb strings.Builder
+		m.writeHistoryContext(&sb)
+		got := sb.String()
+
+		for _, want := range []string{
+			"Branch: feat/login (suggests type: feat)\n",
+			"- feat(auth): add login form\n",
+			"- test(auth): cover login form\n",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("writeHistoryContext() should contain %q, got:\n%s", want, got)
+			}
+		}
+	})
+}
+
+func TestParseStructuredMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    structuredMessage
+		wantErr bool
+	}{
+		{
+			name: "plain json",
+			raw:  `{"type":"feat","scope":"auth","subject":"add login","body":["add form"]}`,
+			want: structuredMessage{Type: "feat", Scope: "auth", Subject: "add login", Body: []string{"add form"}},
+		},
+		{
+			name: "fenced with chatter",
+			raw:  "Here you go:\n```json\n{\"type\":\" FIX \",\"scope\":\"ui\",\"subject\":\"fix crash \"}\n```",
+			want: structuredMessage{Type: "fix", Scope: "ui", Subject: "fix crash"},
+		},
+		{name: "no json", raw: "feat(auth): add login", wantErr: true},
+		{name: "broken json", raw: `{"type": "feat",`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseStructuredMessage(tt.raw)
+			if tt.wantErr {
+				if !errors.Is(err, errNotJSON) {
+					t.Errorf("parseStructuredMessage() error = %v, want errNotJSON", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseStructuredMessage() error = %v", err)
+			}
+			if got.Type != tt.want.Type || got.Scope != tt.want.Scope || got.Subject != tt.want.Subject || len(got.Body) != len(tt.want.Body) {
+				t.Errorf("parseStructuredMessage() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructuredMessageValidate(t *testing.T) {
+	valid := structuredMessage{Type: "feat", Scope: "auth", Subject: "add login"}
+
+	tests := []struct {
+		name    string
+		modify  func(*structuredMessage)
+		wantErr bool
+	}{
+		{"valid", func(*structuredMessage) {}, false},
+		{"unknown type", func(s *structuredMessage) { s.Type = "feature" }, true},
+		{"empty scope", func(s *structuredMessage) { s.Scope = "" }, true},
+		{"empty subject", func(s *structuredMessage) { s.Subject = "" }, true},
+		{"long subject", func(s *structuredMessage) { s.Subject = strings.Repeat("a", maxSubjectLen+1) }, true},
+		{"multi-line subject", func(s *structuredMessage) { s.Subject = "add\nlogin" }, true},
+		{"trailing period", func(s *structuredMessage) { s.Subject = "add login." }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := valid
+			tt.modify(&msg)
+			if err := msg.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCommitJSONSystemPrompt(t *testing.T) {
+	m := aiModel{client: &llm.Client{}}
+	prompt := m.commitJSONSystemPrompt()
+	for _, ct := range consts.CommitTypes {
+		if !strings.Contains(prompt, ct.Name) {
+			t.Errorf("prompt should list type %q, got:\n%s", ct.Name, prompt)
+		}
+	}
+	if want := fmt.Sprintf("max %d chars", maxSubjectLen); !strings.Contains(prompt, want) {
+		t.Errorf("prompt should limit the subject with %q, got:\n%s", want, prompt)
+	}
+	if strings.Contains(prompt, "%!") || strings.Contains(prompt, "OUTPUT ONLY THE COMMIT MESSAGE") {
+		t.Errorf("prompt should ask for JSON only, got:\n%s", prompt)
+	}
+}
+
+func TestExampleJSON(t *testing.T) {
+	got := exampleJSON("feat(api): add auth <jwt>\n\n- validate tokens\n- add profile endpoint")
+	want := `{"type":"feat","scope":"api","subject":"add auth <jwt>","body":["validate tokens","add profile endpoint"]}`
+	if got != want {
+		t.Errorf("exampleJSON() = %s, want %s", got, want)
+	}
+
+	m := aiModel{files: []git.FileDiff{{Path: "main.go"}}, summaries: []string{"add entry point"}, client: &llm.Client{}}
+	prompt := m.finalJSONPrompt()
+	if !strings.Contains(prompt, "Output:\n{\"type\":") {
+		t.Errorf("JSON prompt should show the example as JSON, got:\n%s", prompt)
+	}
+}
+
+func TestStructuredMessageString(t *testing.T) {
+	msg := structuredMessage{
+		Type:    "feat",
+		Scope:   "auth",
+		Subject: "add login",
+		Body:    []string{"- add form", "validate input", "  "},
+	}
+	want := "feat(auth): add login\n\n- add form\n- validate input"
+	if got := msg.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	msg.Body = nil
+	if got := msg.String(); got != "feat(auth): add login" {
+		t.Errorf("String() = %q, want %q", got, "feat(auth): add login")
+	}
+}
+
+func TestNormalizeAIMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{
+			name: "already valid",
+			raw:  "feat(auth): add login\n\n- add form",
+			want: "feat(auth): add login\n\n- add form",
+		},
+		{
+			name: "code fence and preamble",
+			raw:  "Here is the commit message:\n\n```\nfeat(auth): add login\n\n- add form\n```\n",
+			want: "feat(auth): add login\n\n- add form",
+		},
+		{
+			name: "type synonym",
+			raw:  "Feature(auth): add login",
+			want: "feat(auth): add login",
+		},
+		{
+			name: "capitalized subject with trailing period",
+			raw:  "fix(ui): Fix crash on resize.",
+			want: "fix(ui): fix crash on resize",
+		},
+		{
+			name: "acronym kept",
+			raw:  "docs(readme): README cleanup",
+			want: "docs(readme): README cleanup",
+		},
+		{
+			name: "markdown bold header and missing blank line",
+			raw:  "**chore(deps): bump cobra**\n- bump to v1.8",
+			want: "chore(deps): bump cobra\n\n- bump to v1.8",
+		},
+		{
+			name: "missing scope",
+			raw:  "test: cover parser",
+			want: "test(general): cover parser",
+		},
+		{
+			name: "breaking marker moved to a footer",
+			raw:  "refactor(api)!: drop v1",
+			want: "refactor(api): drop v1\n\nBREAKING CHANGE: drop v1",
+		},
+		{
+			name: "breaking marker without scope",
+			raw:  "feat!: remove v1 routes\n\n- drop handlers",
+			want: "feat(general): remove v1 routes\n\n- drop handlers\n\nBREAKING CHANGE: remove v1 routes",
+		},
+		{
+			name: "breaking marker with a footer",
+			raw:  "refactor(api)!: drop v1\n\nBREAKING CHANGE: clients must use v2",
+			want: "refactor(api): drop v1\n\nBREAKING CHANGE: clients must use v2",
+		},
+		{
+			name: "no header",
+			raw:  "Updated some files",
+			want: "Updated some files",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeAIMessage(tt.raw); got != tt.want {
+				t.Errorf("normalizeAIMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAIMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    int
+	}{
+		{"valid", "feat(auth): add login\n\n- add form", 0},
+		{"no header", "Updated some files", 1},
+		{"unknown type", "wip(auth): add login", 1},
+		{"long subject", "feat(auth): " + strings.Repeat("a", maxSubjectLen+1), 1},
+		{"trailing period", "feat(auth): add login.", 1},
+		{"no blank line", "feat(auth): add login\n- add form", 1},
+		{"several problems", "wip(auth): " + strings.Repeat("a", maxSubjectLen) + ".", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkAIMessage(tt.message); len(got) != tt.want {
+				t.Errorf("checkAIMessage() = %v, want %d problems", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCandidateTemperature(t *testing.T) {
+	tests := []struct {
+		base float64
+		i    int
+		want float64
+	}{
+		{0.3, 0, 0.3},
+		{0.3, 1, 0.5},
+		{0.3, 2, 0.7},
+		{0.7, 4, 1.0},
+	}
+
+	for _, tt := range tests {
+		if got := candidateTemperature(tt.base, tt.i); got < tt.want-1e-9 || got > tt.want+1e-9 {
+			t.Errorf("candidateTemperature(%v, %d) = %v, want %v", tt.base, tt.i, got, tt.want)
+		}
+	}
+}
+
+func TestAIPreviewModelCandidates(t *testing.T) {
+	candidates := []string{
+		"feat(auth): add login",
+		"feat(auth): Add login form.",
+		"feat(auth): implement login",
+	}
+	m := newAIPreviewModel(candidates, strategyPerFile)
+	if m.message != candidates[0] || len(m.problems) != 0 {
+		t.Fatalf("initial message = %q, problems = %v", m.message, m.problems)
+	}
+
+	press := func(m aiPreviewModel, key string) aiPreviewModel {
+		var msg tea.KeyMsg
+		switch key {
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "up":
+			msg = tea.KeyMsg{Type: tea.KeyUp}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		updated, _ := m.Update(msg)
+		return updated.(aiPreviewModel)
+	}
+
+	m = press(m, "down")
+	if m.current != 1 || m.message != candidates[1] {
+		t.Errorf("after down: current = %d, message = %q", m.current, m.message)
+	}
+	if len(m.problems) == 0 {
+		t.Error("after down: expected problems for candidate with trailing period")
+	}
+
+	m = press(m, "3")
+	if m.current != 2 || m.message != candidates[2] {
+		t.Errorf("after 3: current = %d, message = %q", m.current, m.message)
+	}
+
+	m = press(m, "down")
+	if m.current != 2 {
+		t.Errorf("down past last: current = %d, want 2", m.current)
+	}
+
+	m = press(m, "9")
+	if m.current != 2 {
+		t.Errorf("out of range number: current = %d, want 2", m.current)
+	}
+
+	m = press(m, "up")
+	if m.current != 1 {
+		t.Errorf("after up: current = %d, want 1", m.current)
+	}
+}
+
+func TestAIModelForRegenerate(t *testing.T) {
+	m := aiModel{
+		summaries: []string{"added login"},
+		phase:     "analyzing",
+		finalMsgs: []string{"feat(auth): add login"},
+		done:      true,
+		err:       errors.New("boom"),
+	}
+
+	r := m.forRegenerate()
+	if r.phase != "generating" || r.done || r.err != nil || r.finalMsgs != nil {
+		t.Errorf("forRegenerate() = phase %q, done %v, err %v, finalMsgs %v", r.phase, r.done, r.err, r.finalMsgs)
+	}
+	if r.ctx == nil || r.cancel == nil {
+		t.Error("forRegenerate() should create a new context")
+	}
+	if len(r.summaries) != 1 || r.summaries[0] != "added login" {
+		t.Errorf("forRegenerate() summaries = %v, want kept", r.summaries)
+	}
+	r.cancel()
+}
+
+func TestAppendRefineHistory(t *testing.T) {
+	var history []llm.Turn
+	for i := 0; i < 5; i++ {
+		history = appendRefineHistory(history, fmt.Sprintf("message %d", i), fmt.Sprintf("instruction %d", i))
+	}
+
+	if len(history) != 2*consts.LLMRefineHistoryTurns {
+		t.Fatalf("len(history) = %d, want %d", len(history), 2*consts.LLMRefineHistoryTurns)
+	}
+	if history[0].Role != llm.RoleAssistant || history[0].Content != "message 2" {
+		t.Errorf("history[0] = %+v, want oldest kept assistant message 2", history[0])
+	}
+	last := history[len(history)-1]
+	if last.Role != llm.RoleUser || !strings.Contains(last.Content, "instruction 4") {
+		t.Errorf("last turn = %+v, want user instruction 4", last)
+	}
+}
+
+func TestAIPreviewModelRefine(t *testing.T) {
+	m := newAIPreviewModel([]string{"feat(auth): add login"}, strategyPerFile)
+
+	update := func(m aiPreviewModel, msg tea.Msg) aiPreviewModel {
+		updated, _ := m.Update(msg)
+		return updated.(aiPreviewModel)
+	}
+
+	m = update(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	if !m.refining {
+		t.Fatal("f should open the refine input")
+	}
+
+	// Empty instruction is ignored
+	m = update(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.refine {
+		t.Error("empty instruction should not submit")
+	}
+
+	// Keys go to the input, not the buttons
+	m = update(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("make it a fix")})
+	if m.cancelled || m.retry {
+		t.Error("typing in the refine input should not trigger actions")
+	}
+
+	// Esc returns to the buttons
+	m = update(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.refining || m.cancelled {
+		t.Errorf("esc: refining = %v, cancelled = %v, want false, false", m.refining, m.cancelled)
+	}
+
+	m = update(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	m = update(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("scope auth")})
+	m = update(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.refine || m.input.Value() != "scope auth" {
+		t.Errorf("submit: refine = %v, value = %q", m.refine, m.input.Value())
+	}
+}
+
+func TestAIPreviewModelError(t *testing.T) {
+	m := newAIPreviewModel([]string{"feat(auth): add login"}, strategyPerFile)
+	m.err = errors.New("refinement failed: timeout")
+
+	view := m.View()
+	if !strings.Contains(view, "refinement failed: timeout") {
+		t.Error("View() should show the error of the previous action")
+	}
+	if !strings.Contains(view, "feat(auth): add login") || !strings.Contains(view, "Commit") {
+		t.Error("View() should keep the message and the buttons after an error")
+	}
+}
+
+func TestParsePRDescription(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		wantTitle string
+		wantBody  string
+	}{
+		{
+			name:      "title and body",
+			raw:       "feat(auth): add login\n\n## Summary\nAdds login.",
+			wantTitle: "feat(auth): add login",
+			wantBody:  "## Summary\nAdds login.",
+		},
+		{
+			name:      "title prefix and fences",
+			raw:       "```markdown\nTitle: feat(auth): add login\n\n## Summary\nAdds login.\n```",
+			wantTitle: "feat(auth): add login",
+			wantBody:  "## Summary\nAdds login.",
+		},
+		{
+			name:      "code blocks in the body are kept",
+			raw:       "feat(cli): add flag\n\n## Usage\n```sh\ngit pr --draft\n```\n\nDone.",
+			wantTitle: "feat(cli): add flag",
+			wantBody:  "## Usage\n```sh\ngit pr --draft\n```\n\nDone.",
+		},
+		{
+			name:      "fenced answer with a code block",
+			raw:       "```\nfeat(cli): add flag\n\n```sh\ngit pr\n```\n```\n",
+			wantTitle: "feat(cli): add flag",
+			wantBody:  "```sh\ngit pr\n```",
+		},
+		{
+			name:      "heading title",
+			raw:       "# **fix(ui): fix crash**\n",
+			wantTitle: "fix(ui): fix crash",
+		},
+		{name: "empty", raw: "  \n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parsePRDescription(tt.raw)
+			if got.Title != tt.wantTitle || got.Body != tt.wantBody {
+				t.Errorf("parsePRDescription() = %+v, want title %q, body %q", got, tt.wantTitle, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestGroupCommitsByType(t *testing.T) {
+	groups := groupCommitsByType([]string{
+		"feat(auth): add login",
+		"fix(auth): handle empty password",
+		"Merge stuff",
+		"feature(ui): add button",
+	})
+
+	want := map[string]int{"feat": 2, "fix": 1, "other": 1}
+	if len(groups) != len(want) {
+		t.Fatalf("groupCommitsByType() = %+v, want %d groups", groups, len(want))
+	}
+	if groups[0].name != "feat" {
+		t.Errorf("first group = %q, want feat", groups[0].name)
+	}
+	for _, g := range groups {
+		if len(g.subjects) != want[g.name] {
+			t.Errorf("group %q has %d subjects, want %d", g.name, len(g.subjects), want[g.name])
+		}
+	}
+}
+
+func TestBuildPRPrompt(t *testing.T) {
+	m := aiModel{
+		files:      []git.FileDiff{{Path: "auth.go"}, {Path: "go.sum"}},
+		summaries:  []string{"added login handler", "updated lock file (+1/-1 lines, not analyzed)"},
+		fileStatus: []int{2, 3},
+		ignored:    []string{"vendor/x.go"},
+		strategy:   strategyPerFile,
+		pr: &prContext{
+			base:    "main",
+			branch:  "feat/login",
+			commits: []string{"feat(auth): add login"},
+		},
+	}
+
+	got := m.buildPRPrompt()
+	for _, want := range []string{
+		"merging feat/login into main",
+		"feat:\n- feat(auth): add login\n",
+		"- auth.go: added login handler\n",
+		"- go.sum: updated lock file",
+		"- also changed: vendor/x.go\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("buildPRPrompt() should contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestParseFindings(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []ReviewFinding
+	}{
+		{
+			name: "none",
+			raw:  "NONE",
+			want: nil,
+		},
+		{
+			name: "bracketed severities",
+			raw:  "[high] nil pointer dereference in Load\n[low] leftover fmt.Println",
+			want: []ReviewFinding{
+				{Severity: SeverityHigh, Message: "nil pointer dereference in Load"},
+				{Severity: SeverityLow, Message: "leftover fmt.Println"},
+			},
+		},
+		{
+			name: "bullets and aliases",
+			raw:  "- CRITICAL: SQL built from user input\n* Warning - error ignored in Close",
+			want: []ReviewFinding{
+				{Severity: SeverityHigh, Message: "SQL built from user input"},
+				{Severity: SeverityMedium, Message: "error ignored in Close"},
+			},
+		},
+		{
+			name: "unformatted output",
+			raw:  "The loop never terminates\nwhen n is negative.",
+			want: []ReviewFinding{
+				{Severity: SeverityLow, Message: "The loop never terminates when n is negative."},
+			},
+		},
+		{
+			name: "code fences and blank lines",
+			raw:  "```\n\n[medium] race on counter\n```",
+			want: []ReviewFinding{
+				{Severity: SeverityMedium, Message: "race on counter"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseFindings(tt.raw)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("parseFindings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildReviewPrompt(t *testing.T) {
+	got := buildReviewPrompt(git.FileDiff{Path: "main.go", Diff: "+fmt.Println(x)"})
+	for _, want := range []string{"File: main.go\n", "+fmt.Println(x)", "Findings:"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("buildReviewPrompt() should contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestCollectReviews(t *testing.T) {
+	m := aiModel{
+		files:      []git.FileDiff{{Path: "a.go"}, {Path: "b.go"}, {Path: "go.sum"}},
+		summaries:  []string{"[high] unchecked error", "NONE", "updated lock file"},
+		fileStatus: []int{2, 2, 3},
+	}
+
+	got := collectReviews(m)
+	if len(got) != 1 || got[0].Path != "a.go" {
+		t.Fatalf("collectReviews() = %v, want findings for a.go only", got)
+	}
+	if got[0].Findings[0].Severity != SeverityHigh {
+		t.Errorf("severity = %q, want %q", got[0].Findings[0].Severity, SeverityHigh)
+	}
+}
+
+func TestNewAIModelReview(t *testing.T) {
+	files := []git.FileDiff{{Path: "a.go", Diff: "+x"}}
+	m := newAIModel(aiInput{files: files, review: true}, llm.NewClient())
+	if m.strategy != strategyPerFile {
+		t.Errorf("strategy = %q, want %q for reviews", m.strategy, strategyPerFile)
+	}
+	if m.done {
+		t.Error("review with analyzable files should not be done")
+	}
+}
+
+func TestReviewConfirmModel(t *testing.T) {
+	files := []FileReview{{Path: "a.go", Findings: []ReviewFinding{{Severity: SeverityHigh, Message: "bug"}}}}
+
+	tests := []struct {
+		name        string
+		keys        []string
+		wantProceed bool
+	}{
+		{name: "enter continues", keys: []string{"enter"}, wantProceed: true},
+		{name: "abort key", keys: []string{"a"}, wantProceed: false},
+		{name: "select abort", keys: []string{"right", "enter"}, wantProceed: false},
+		{name: "continue key", keys: []string{"right", "c"}, wantProceed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var model tea.Model = newReviewConfirmModel(files)
+			for _, k := range tt.keys {
+				var msg tea.KeyMsg
+				switch k {
+				case "enter":
+					msg = tea.KeyMsg{Type: tea.KeyEnter}
+				case "right":
+					msg = tea.KeyMsg{Type: tea.KeyRight}
+				default:
+					msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+				}
+				model, _ = model.Update(msg)
+			}
+			if got := model.(reviewConfirmModel).proceed; got != tt.wantProceed {
+				t.Errorf("proceed = %v, want %v", got, tt.wantProceed)
+			}
+		})
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   Fields
+		wantErr  string
+		wantBody string
+	}{
+		{name: "valid", fields: Fields{Type: "feat", Scope: "api", Subject: "add endpoint", Body: "details"}, wantBody: "details"},
+		{name: "body defaults to subject", fields: Fields{Type: "fix", Scope: "ui", Subject: "fix crash"}, wantBody: "fix crash"},
+		{name: "missing type", fields: Fields{Scope: "api", Subject: "x"}, wantErr: "Type cannot be empty"},
+		{name: "unknown type", fields: Fields{Type: "feature", Scope: "api", Subject: "x"}, wantErr: "unknown commit type"},
+		{name: "missing scope", fields: Fields{Type: "feat", Subject: "x"}, wantErr: "Scope cannot be empty"},
+		{name: "invalid scope", fields: Fields{Type: "feat", Scope: "a:b", Subject: "x"}, wantErr: "Scope cannot contain"},
+		{name: "long subject", fields: Fields{Type: "feat", Scope: "api", Subject: strings.Repeat("x", maxSubjectLen+1)}, wantErr: "Subject should be"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := BuildMessage(tt.fields)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("BuildMessage() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildMessage() error = %v", err)
+			}
+			if msg.Type != tt.fields.Type || msg.Scope != tt.fields.Scope || msg.Subject != tt.fields.Subject {
+				t.Errorf("BuildMessage() = %+v, fields not kept", msg)
+			}
+			if msg.Body != tt.wantBody {
+				t.Errorf("Body = %q, want %q", msg.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestAskFields(t *testing.T) {
+	input := "\nfix\n\napi\n" + strings.Repeat("x", maxSubjectLen+1) + "\nhandle nil\nfirst\n- second\n\nCloses #1\n"
+	var out strings.Builder
+	p := common.NewPrompter(strings.NewReader(input), &out)
+
+	got, err := askFields(p, "")
+	if err != nil {
+		t.Fatalf("askFields() error = %v", err)
+	}
+	want := Fields{Type: "fix", Scope: "api", Subject: "handle nil", Body: "first\n- second", Footer: "Closes #1"}
+	if got != want {
+		t.Errorf("askFields() = %+v, want %+v", got, want)
+	}
+	for _, msg := range []string{"Scope cannot be empty", "Subject should be"} {
+		if !strings.Contains(out.String(), msg) {
+			t.Errorf("output should contain validation error %q", msg)
+		}
+	}
+}
+
+func TestAskFieldsDefaultType(t *testing.T) {
+	p := common.NewPrompter(strings.NewReader("\napi\nadd\n\n\n"), io.Discard)
+	got, err := askFields(p, "docs")
+	if err != nil {
+		t.Fatalf("askFields() error = %v", err)
+	}
+	if got.Type != "docs" {
+		t.Errorf("Type = %q, want docs from the initial type", got.Type)
+	}
+}
+
+func TestPlainResult(t *testing.T) {
+	if r := plainResult(common.ErrPromptClosed); !r.Cancelled {
+		t.Error("closed input should cancel")
+	}
+	if r := plainResult(errors.New("boom")); r.Err == nil || r.Cancelled {
+		t.Error("other errors should be reported")
+	}
+}
+
+func TestNextListMarker(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{line: "- item", want: "- "},
+		{line: "  * nested", want: "  * "},
+		{line: "1. first", want: "2. "},
+		{line: "9) ninth", want: "10) "},
+		{line: "plain text", want: ""},
+		{line: "-dash", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			marker := listMarker(tt.line)
+			if got := nextListMarker(marker); got != tt.want {
+				t.Errorf("nextListMarker(%q) = %q, want %q", marker, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapBody(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		width int
+		want  string
+	}{
+		{name: "short lines kept", body: "one\ntwo", width: 10, want: "one\ntwo"},
+		{name: "paragraph", body: "aaa bbb ccc ddd", width: 8, want: "aaa bbb\nccc ddd"},
+		{name: "list item", body: "- aaa bbb ccc", width: 8, want: "- aaa\n  bbb\n  ccc"},
+		{name: "long word", body: "abcdefghij x", width: 5, want: "abcdefghij\nx"},
+		{name: "trailing spaces", body: "abc   ", width: 5, want: "abc"},
+		{name: "spacing kept", body: "one.  two   three four", width: 12, want: "one.  two\nthree four"},
+		{name: "indented code", body: "run:\n    go test ./... -run TestWrapBody -v", width: 10, want: "run:\n    go test ./... -run TestWrapBody -v"},
+		{name: "tab code", body: "\tif err != nil { return err }", width: 10, want: "\tif err != nil { return err }"},
+		{name: "nested list wrapped", body: "    - aaa bbb ccc", width: 10, want: "    - aaa\n      bbb\n      ccc"},
+		{
+			name:  "fenced block",
+			body:  "aaa bbb ccc\n```\nx := []int{1,  2, 3} // long comment\n```",
+			width: 8,
+			want:  "aaa bbb\nccc\n```\nx := []int{1,  2, 3} // long comment\n```",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapBody(tt.body, tt.width); got != tt.want {
+				t.Errorf("wrapBody() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInputsResultWrap(t *testing.T) {
+	m := newInputsModel("feat")
+	long := strings.Repeat("word ", 20) + "end"
+	m.inputs[bodyIndex].area.SetValue(long)
+
+	if got := m.result().body; got != wrapBody(long, bodyWrapWidth) || !strings.Contains(got, "\n") {
+		t.Errorf("result().body = %q, want the body wrapped at %d columns", got, bodyWrapWidth)
+	}
+
+	m.bodyEdited = true
+	if got := m.result().body; got != long {
+		t.Errorf("result().body = %q, want the editor content unchanged", got)
+	}
+}
+
+func TestVisibleFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		heights   []int
+		focus     int
+		available int
+		wantStart int
+		wantEnd   int
+	}{
+		{name: "all fit", heights: []int{1, 1, 6, 1}, focus: 0, available: 10, wantStart: 0, wantEnd: 4},
+		{name: "focus first", heights: []int{1, 1, 6, 1}, focus: 0, available: 3, wantStart: 0, wantEnd: 2},
+		{name: "focus body", heights: []int{1, 1, 6, 1}, focus: 2, available: 7, wantStart: 2, wantEnd: 4},
+		{name: "submit focused", heights: []int{1, 1, 6, 1}, focus: 4, available: 2, wantStart: 3, wantEnd: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := visibleFields(tt.heights, tt.focus, tt.available)
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("visibleFields() = (%d, %d), want (%d, %d)", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestInputsBodyEditing(t *testing.T) {
+	var model tea.Model = newInputsModel("feat")
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyTab},
+		{Type: tea.KeyTab},
+		{Type: tea.KeyRunes, Runes: []rune("- one")},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyRunes, Runes: []rune("two")},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyRunes, Runes: []rune("done")},
+	}
+	for _, k := range keys {
+		model, _ = model.Update(k)
+	}
+
+	m := model.(inputsModel)
+	if m.focusIndex != bodyIndex {
+		t.Fatalf("focusIndex = %d, want %d", m.focusIndex, bodyIndex)
+	}
+	want := "- one\n- two\ndone"
+	if got := m.inputs[bodyIndex].value(); got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+
+	// Up on the last line moves within the body, tab leaves it
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if model.(inputsModel).focusIndex != bodyIndex {
+		t.Error("up inside the body should stay in the body")
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if model.(inputsModel).focusIndex != bodyIndex+1 {
+		t.Error("tab should move to the next field")
+	}
+}
+
+func TestComposerBackNavigation(t *testing.T) {
+	var model tea.Model = newComposerModel("fix")
+	send := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			model, _ = model.Update(k)
+		}
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	tab := tea.KeyMsg{Type: tea.KeyTab}
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	esc := tea.KeyMsg{Type: tea.KeyEsc}
+
+	send(enter, runes("api"), tab, runes("fix crash"))
+	m := model.(composerModel)
+	if m.step != stepFields || m.commitType != "fix" {
+		t.Fatalf("step = %d, type = %q, want fields step with fix", m.step, m.commitType)
+	}
+	if got := m.previewMessage(); got.Scope != "api" || got.Subject != "fix crash" || got.Body != "fix crash" {
+		t.Errorf("preview = %+v, want live field values", got)
+	}
+
+	// Submit moves to the confirmation, esc goes back with the values kept
+	send(tab, tab, tab, enter)
+	m = model.(composerModel)
+	if m.step != stepConfirm {
+		t.Fatalf("step = %d, want confirm", m.step)
+	}
+	if m.message.Type != "fix" || m.message.Scope != "api" {
+		t.Errorf("message = %+v, want fix(api)", m.message)
+	}
+
+	send(esc, esc, tea.KeyMsg{Type: tea.KeyDown}, enter)
+	m = model.(composerModel)
+	if m.step != stepFields || m.commitType != "docs" {
+		t.Fatalf("step = %d, type = %q, want fields step with docs", m.step, m.commitType)
+	}
+	if got := m.fields.result().scope; got != "api" {
+		t.Errorf("scope = %q, want api kept after going back", got)
+	}
+
+	send(enter, runes("y"))
+	m = model.(composerModel)
+	if !m.confirmed || m.message.Type != "docs" {
+		t.Errorf("confirmed = %v, type = %q, want confirmed docs commit", m.confirmed, m.message.Type)
+	}
+}
+
+func TestComposerValidation(t *testing.T) {
+	var model tea.Model = newComposerModel("feat")
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m := model.(composerModel); m.step != stepFields {
+		t.Errorf("step = %d, want fields step when the scope is empty", m.step)
+	}
+}
+
+func TestComposerAIChoice(t *testing.T) {
+	var model tea.Model = newComposerModel("")
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+
+	if m := model.(composerModel); m.choice != aiGenerateChoice || cmd == nil {
+		t.Errorf("choice = %q, want AI generation and quit", m.choice)
+	}
+}
+
+func TestCollectHunks(t *testing.T) {
+	diff := "diff --git a/a.txt b/a.txt\n--- a/a.txt\n+++ b/a.txt\n@@ -1 +1 @@\n-1\n+1x\n@@ -9 +9 @@\n-9\n+9x\n"
+	hunks := collectHunks(diff, true)
+	if len(hunks) != 2 {
+		t.Fatalf("len(hunks) = %d, want 2", len(hunks))
+	}
+	if !hunks[1].staged || !strings.HasPrefix(hunks[1].hunk, "@@ -9") {
+		t.Errorf("hunks[1] = %+v, want the staged second hunk", hunks[1])
+	}
+	if hunks[0].header != "diff --git a/a.txt b/a.txt\n--- a/a.txt\n+++ b/a.txt" {
+		t.Errorf("header = %q", hunks[0].header)
+	}
+	if collectHunks("", false) != nil {
+		t.Error("collectHunks() of an empty diff should be nil")
+	}
+}
+
+func TestUntrackedPreview(t *testing.T) {
+	dir := t.TempDir()
+	text := filepath.Join(dir, "new.go")
+	binary := filepath.Join(dir, "image.png")
+	_ = os.WriteFile(text, []byte("package main\n\nfunc main() {}\n"), 0o644)
+	_ = os.WriteFile(binary, []byte{0x89, 'P', 'N', 'G', 0}, 0o644)
+
+	if got, want := untrackedPreview(text), "+package main\n+\n+func main() {}"; got != want {
+		t.Errorf("untrackedPreview() = %q, want %q", got, want)
+	}
+	if got := untrackedPreview(binary); got != "Binary file" {
+		t.Errorf("untrackedPreview() = %q, want Binary file", got)
+	}
+}
+
+func TestDiffStyleFor(t *testing.T) {
+	s := newDiffStyles()
+	tests := []struct {
+		line string
+		want lipgloss.Style
+	}{
+		{line: "diff --git a/a b/a", want: s.header},
+		{line: "+++ b/a", want: s.header},
+		{line: "@@ -1 +1 @@", want: s.hunk},
+		{line: "+added", want: s.added},
+		{line: "-removed", want: s.removed},
+		{line: " context", want: s.context},
+	}
+
+	for _, tt := range tests {
+		if got := s.styleFor(tt.line); got.GetForeground() != tt.want.GetForeground() {
+			t.Errorf("styleFor(%q) foreground = %v, want %v", tt.line, got.GetForeground(), tt.want.GetForeground())
+		}
+	}
+}
+
+func TestStagingModelNavigation(t *testing.T) {
+	files := []git.FileStatus{
+		{Path: "missing-a.go", Index: ' ', WorkTree: 'M'},
+		{Path: "missing-b.go", Index: '?', WorkTree: '?', Untracked: true},
+	}
+	var model tea.Model = newStagingModel(files)
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	m := model.(stagingModel)
+	if m.cursor != 1 || m.hunkMode {
+		t.Errorf("cursor = %d, hunkMode = %v, want untracked file without hunk mode", m.cursor, m.hunkMode)
+	}
+
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(stagingModel)
+	if !errors.Is(m.err, errNothingStaged) || m.done || cmd != nil {
+		t.Errorf("err = %v, done = %v, want to stay when nothing is staged", m.err, m.done)
+	}
+}
+
+func TestNeedsStaging(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []git.FileStatus
+		want  bool
+	}{
+		{name: "clean tree", files: nil, want: false},
+		{name: "all staged", files: []git.FileStatus{{Path: "a.go", Index: 'M', WorkTree: ' '}}, want: false},
+		{name: "partly staged", files: []git.FileStatus{{Path: "a.go", Index: 'M', WorkTree: 'M'}}, want: true},
+		{name: "only untracked", files: []git.FileStatus{{Path: "b.go", Index: '?', WorkTree: '?', Untracked: true}}, want: true},
+		{name: "unstaged tracked", files: []git.FileStatus{{Path: "a.go", Index: ' ', WorkTree: 'M'}}, want: true},
+		{
+			name: "staged with untracked",
+			files: []git.FileStatus{
+				{Path: "a.go", Index: 'M', WorkTree: ' '},
+				{Path: "b.go", Index: '?', WorkTree: '?', Untracked: true},
+			},
+			want: false,
+		},
+		{
+			name: "staged with unstaged tracked",
+			files: []git.FileStatus{
+				{Path: "a.go", Index: 'A', WorkTree: ' '},
+				{Path: "c.go", Index: ' ', WorkTree: 'D'},
+				{Path: "b.go", Index: '?', WorkTree: '?', Untracked: true},
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NeedsStaging(tt.files); got != tt.want {
+				t.Errorf("NeedsStaging() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffPaneKeys(t *testing.T) {
+	p := newDiffPane()
+	p.loaded = true
+	p.files = git.SplitDiffByFile("diff --git a/a.go b/a.go\n+1\n+2\n+3\n+4\n+5\n+6\ndiff --git a/b.go b/b.go\n+1")
+	p.setSize(40, 4)
+
+	if p.handleKey(tea.KeyMsg{Type: tea.KeyPgDown}) {
+		t.Error("pgdown should be ignored while the pane is hidden")
+	}
+	if !p.handleKey(tea.KeyMsg{Type: tea.KeyCtrlG}) || !p.visible {
+		t.Fatal("ctrl+g should show the pane")
+	}
+
+	p.handleKey(tea.KeyMsg{Type: tea.KeyPgDown})
+	p.handleKey(tea.KeyMsg{Type: tea.KeyPgDown})
+	if p.offset != 3 {
+		t.Errorf("offset = %d, want 3 (clamped to the last page)", p.offset)
+	}
+
+	p.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n"), Alt: true})
+	if p.file != 1 || p.offset != 0 {
+		t.Errorf("file = %d, offset = %d, want second file from the top", p.file, p.offset)
+	}
+	p.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n"), Alt: true})
+	if p.file != 1 {
+		t.Errorf("file = %d, want to stay on the last file", p.file)
+	}
+	if !strings.Contains(p.View(), "Staged diff 2/2") {
+		t.Error("View() should show the file position")
+	}
+}
+
+func TestDiffPaneEmpty(t *testing.T) {
+	p := newDiffPane()
+	p.loaded = true
+	p.toggle()
+
+	if !strings.Contains(p.View(), "No staged changes") {
+		t.Error("View() should say there are no staged changes")
+	}
+	p.toggle()
+	if p.View() != "" {
+		t.Error("View() should be empty while the pane is hidden")
+	}
+}
+
+func TestAIPreviewModelDiffToggle(t *testing.T) {
+	m := newAIPreviewModel([]string{"feat(api): add endpoint"}, "")
+	m.diff.loaded = true
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if model.(aiPreviewModel).diff.visible {
+		t.Error("d should not toggle the staged diff")
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	if !model.(aiPreviewModel).diff.visible {
+		t.Error("ctrl+g should show the staged diff")
+	}
+
+	// The pane is sized on resize, not only while rendering
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 60, Height: 40})
+	pane := model.(aiPreviewModel).diff
+	if pane.width != 54 || pane.height != 40-lipgloss.Height(model.(aiPreviewModel).messageView())-10 {
+		t.Errorf("diff pane size = %dx%d after resize", pane.width, pane.height)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	if model.(aiPreviewModel).diff.visible {
+		t.Error("ctrl+g should hide the staged diff")
+	}
+}
@@ -0,0 +1,193 @@
+package commit
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mritd/gitflow-toolkit/v3/consts"
+)
+
+// typeAliases maps commit type synonyms returned by models to valid commit types.
+var typeAliases = map[string]string{
+	// feat
+	"feature":  consts.Feat,
+	"features": consts.Feat,
+	"add":      consts.Feat,
+	// fix
+	"bugfix": consts.Fix,
+	"bug":    consts.Fix,
+	"fixes":  consts.Fix,
+	"fixed":  consts.Fix,
+	// docs
+	"doc":           consts.Docs,
+	"document":      consts.Docs,
+	"documentation": consts.Docs,
+	// style
+	"format":     consts.Style,
+	"formatting": consts.Style,
+	"lint":       consts.Style,
+	// refactor
+	"refact":      consts.Refactor,
+	"refactoring": consts.Refactor,
+	"cleanup":     consts.Refactor,
+	// test
+	"tests":   consts.Test,
+	"testing": consts.Test,
+	// chore
+	"chores": consts.Chore,
+	"build":  consts.Chore,
+	"ci":     consts.Chore,
+	"deps":   consts.Chore,
+	"revert": consts.Chore,
+	// perf
+	"performance": consts.Perf,
+	"optimize":    consts.Perf,
+	// hotfix
+	"hot-fix": consts.Hotfix,
+}
+
+// headerLine matches a conventional commit header such as "feat(scope): subject".
+var headerLine = regexp.MustCompile(`^[A-Za-z-]+(\([^)]*\))?!?:\s*\S`)
+
+// normalizeAIMessage repairs common model mistakes in a generated commit message:
+// code fences, preamble text, markdown decoration, type synonyms, capitalized
+// subjects and trailing periods. A "!" breaking-change marker in the header is
+// moved to a BREAKING CHANGE footer, since headers are always type(scope): subject.
+// Problems it cannot fix are left for checkAIMessage.
+func normalizeAIMessage(raw string) string {
+	// Drop code fences
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+
+	// Drop preamble before the header ("Here is the commit message:")
+	for i, line := range lines {
+		if headerLine.MatchString(stripDecoration(line)) {
+			lines = lines[i:]
+			break
+		}
+	}
+
+	// Drop leading and trailing blank lines
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	header := stripDecoration(lines[0])
+	if !headerLine.MatchString(header) {
+		return strings.Join(lines, "\n")
+	}
+
+	breaking := strings.Contains(header[:strings.Index(header, ":")], "!")
+	msgType, scope, subject := parseHeader(header)
+	msgType = normalizeType(msgType)
+	scope = strings.TrimSpace(scope)
+	if scope == "" {
+		scope = "general"
+	}
+	subject = normalizeSubject(subject)
+
+	result := msgType + "(" + scope + "): " + subject
+
+	// Body is separated from the header by exactly one blank line
+	body := lines[1:]
+	for len(body) > 0 && strings.TrimSpace(body[0]) == "" {
+		body = body[1:]
+	}
+	if len(body) > 0 {
+		result += "\n\n" + strings.Join(body, "\n")
+	}
+	if breaking && !hasBreakingFooter(body) {
+		result += "\n\nBREAKING CHANGE: " + subject
+	}
+
+	return result
+}
+
+// hasBreakingFooter reports whether the lines contain a BREAKING CHANGE footer.
+func hasBreakingFooter(lines []string) bool {
+	for _, line := range lines {
+		if strings.HasPrefix(line, "BREAKING CHANGE:") || strings.HasPrefix(line, "BREAKING-CHANGE:") {
+			return true
+		}
+	}
+	return false
+}
+
+// stripDecoration removes markdown emphasis, quotes and surrounding whitespace from a line.
+func stripDecoration(line string) string {
+	return strings.Trim(strings.TrimSpace(line), "*`\"'")
+}
+
+// normalizeType lowercases the type, drops the breaking-change marker and maps synonyms.
+// normalizeAIMessage keeps the marker as a footer.
+func normalizeType(t string) string {
+	t = strings.ToLower(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(t), "!")))
+	if alias, ok := typeAliases[t]; ok {
+		return alias
+	}
+	return t
+}
+
+// normalizeSubject trims the subject, removes trailing periods and
+// lowercases the first letter unless the first word is an acronym.
+func normalizeSubject(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimRight(s, ".。")
+	s = strings.TrimSpace(s)
+
+	first, size := utf8.DecodeRuneInString(s)
+	if !unicode.IsUpper(first) {
+		return s
+	}
+	word := s
+	if i := strings.IndexAny(s, " -_/"); i > 0 {
+		word = s[:i]
+	}
+	if second, _ := utf8.DecodeRuneInString(word[size:]); unicode.IsUpper(second) {
+		// Acronym such as "API" or "README"
+		return s
+	}
+	return string(unicode.ToLower(first)) + s[size:]
+}
+
+// checkAIMessage validates a commit message against the same rules as the manual inputs.
+// Returns a description of every rule the message breaks.
+func checkAIMessage(message string) []string {
+	lines := splitLines(message)
+	if len(lines) == 0 || !headerLine.MatchString(lines[0]) {
+		return []string{"Header must be in the form type(scope): subject"}
+	}
+
+	msgType, scope, subject := parseHeader(lines[0])
+
+	var problems []string
+	if !isCommitType(msgType) {
+		problems = append(problems, "Type \""+msgType+"\" is not one of: "+strings.Join(commitTypeNames(), ", "))
+	}
+	if err := validateScope(scope); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if err := validateSubject(subject); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if strings.HasSuffix(subject, ".") || strings.HasSuffix(subject, "。") {
+		problems = append(problems, "Subject must not end with a period")
+	}
+	if len(lines) > 1 && lines[1] != "" {
+		problems = append(problems, "Subject must be followed by a blank line")
+	}
+	return problems
+}
@@ -0,0 +1,83 @@
+package commit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mritd/gitflow-toolkit/v3/consts"
+	"github.com/mritd/gitflow-toolkit/v3/internal/ui/common"
+)
+
+// runPlainFlow runs the manual commit flow with line-oriented prompts instead of
+// the TUI, using the same validators as the inputs screen.
+func runPlainFlow(p *common.Prompter, initialType string, luckyPrefix LuckyPrefixFunc) Result {
+	fields, err := askFields(p, initialType)
+	if err != nil {
+		return plainResult(err)
+	}
+
+	msg, err := BuildMessage(fields)
+	if err != nil {
+		return Result{Err: err}
+	}
+
+	p.Println("")
+	p.Println("Commit message:")
+	p.Println(strings.TrimRight(msg.String(), "\n"))
+	p.Println("")
+
+	confirmed, err := p.Confirm("Create this commit?", true)
+	if err != nil {
+		return plainResult(err)
+	}
+	if !confirmed {
+		return Result{Cancelled: true}
+	}
+
+	return commitWithLucky(msg, luckyPrefix, func(result Result, prefix string) Result {
+		p.Println(fmt.Sprintf("Searching for a commit hash starting with %s...", prefix))
+		return runLuckyHeadless(result, prefix)
+	})
+}
+
+// askFields asks for the commit type and message fields one after another.
+func askFields(p *common.Prompter, initialType string) (Fields, error) {
+	var f Fields
+
+	choices := make([]common.Choice, len(consts.CommitTypes))
+	def := -1
+	for i, ct := range consts.CommitTypes {
+		choices[i] = common.Choice{Name: ct.Name, Description: ct.Description}
+		if ct.Name == initialType {
+			def = i
+		}
+	}
+	idx, err := p.Choose("Commit type", choices, def)
+	if err != nil {
+		return f, err
+	}
+	f.Type = choices[idx].Name
+
+	if f.Scope, err = p.AskValid("Scope (e.g. api, ui, core)", validateScope); err != nil {
+		return f, err
+	}
+	if f.Subject, err = p.AskValid(fmt.Sprintf("Subject (imperative mood, max %d chars)", maxSubjectLen), validateSubject); err != nil {
+		return f, err
+	}
+	if f.Body, err = p.AskLines("Body (optional)"); err != nil {
+		return f, err
+	}
+	if f.Footer, err = p.Ask("Footer (optional, e.g. Closes #123)"); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// plainResult converts a prompt error into a result; closed input cancels the commit.
+func plainResult(err error) Result {
+	if errors.Is(err, common.ErrPromptClosed) {
+		return Result{Cancelled: true}
+	}
+	return Result{Err: err}
+}
@@ -0,0 +1,214 @@
+package commit
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/mritd/gitflow-toolkit/v3/consts"
+	"github.com/mritd/gitflow-toolkit/v3/internal/git"
+	"github.com/mritd/gitflow-toolkit/v3/internal/llm"
+)
+
+// prContext describes the branch a PR description is generated for.
+type prContext struct {
+	base    string   // branch the PR targets
+	branch  string   // current branch
+	commits []string // commit subjects on the branch, oldest first
+}
+
+// PRDescription is a generated pull request title and Markdown body.
+type PRDescription struct {
+	Base  string
+	Title string
+	Body  string
+}
+
+// String formats the description as the title followed by the body.
+func (d PRDescription) String() string {
+	if d.Body == "" {
+		return d.Title + "\n"
+	}
+	return d.Title + "\n\n" + d.Body + "\n"
+}
+
+// PRResult represents the result of PR description generation.
+type PRResult struct {
+	Description PRDescription
+	Cancelled   bool
+	Err         error
+}
+
+// RunPRDescription generates a PR title and description for the current branch
+// from its commits and combined diff against the main branch.
+func RunPRDescription(opts Options) PRResult {
+	base := git.MainBranch()
+	branch, err := git.CurrentBranch()
+	if err != nil {
+		return PRResult{Err: fmt.Errorf("failed to get current branch: %w", err)}
+	}
+	if branch == base {
+		return PRResult{Err: fmt.Errorf("current branch is %s, switch to a feature branch first", base)}
+	}
+
+	commits, err := git.GetBranchCommits(base)
+	if err != nil {
+		return PRResult{Err: fmt.Errorf("failed to get commits since %s: %w", base, err)}
+	}
+	if len(commits) == 0 {
+		return PRResult{Err: fmt.Errorf("no commits between %s and %s", base, branch)}
+	}
+
+	diff, err := git.GetBranchDiff(base, llm.GetDiffContext())
+	if err != nil {
+		return PRResult{Err: fmt.Errorf("failed to get diff against %s: %w", base, err)}
+	}
+
+	// Same preparation as commit messages: ignore patterns and secret redaction
+	files, ignored := git.FilterIgnored(git.SplitDiffByFile(diff), git.GetIgnorePatterns())
+	if len(files) == 0 {
+		return PRResult{Err: fmt.Errorf("no changes to describe between %s and %s", base, branch)}
+	}
+	redactions, err := redactFiles(files)
+	if err != nil {
+		return PRResult{Err: err}
+	}
+
+	var cache *llm.Cache
+	if !opts.NoCache {
+		cache = llm.NewDefaultCache()
+	}
+
+	m := newAIModel(aiInput{
+		files:      files,
+		ignored:    ignored,
+		redactions: redactions,
+		cache:      cache,
+		pr:         &prContext{base: base, branch: branch, commits: commits},
+	}, llm.NewClient())
+
+	finalModel, err := tea.NewProgram(m).Run()
+	if err != nil {
+		return PRResult{Err: err}
+	}
+
+	result := finalModel.(aiModel)
+	if result.cancelled {
+		return PRResult{Cancelled: true}
+	}
+	if result.err != nil {
+		return PRResult{Err: result.err}
+	}
+
+	desc := parsePRDescription(result.finalMsgs[0])
+	desc.Base = base
+	return PRResult{Description: desc}
+}
+
+// generatePRDescription generates the PR title and body from the file summaries (or diffs).
+func (m aiModel) generatePRDescription() tea.Cmd {
+	return func() tea.Msg {
+		opt := llm.GenerateOptions{
+			System: consts.LLMPRPrompt,
+		}
+		message, err := m.client.Generate(m.ctx, m.client.GetModel(), m.buildPRPrompt(), opt)
+		if err != nil {
+			return aiFinalGeneratedMsg{err: err}
+		}
+		return aiFinalGeneratedMsg{messages: []string{message}}
+	}
+}
+
+// buildPRPrompt creates the prompt for a PR description: branch, commits grouped
+// by type, and the per-file summaries (or full diffs in single-pass mode).
+func (m aiModel) buildPRPrompt() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Write a pull request description for merging %s into %s.\n\n", m.pr.branch, m.pr.base))
+
+	sb.WriteString("Commits by type:\n")
+	for _, group := range groupCommitsByType(m.pr.commits) {
+		sb.WriteString(group.name + ":\n")
+		for _, subject := range group.subjects {
+			sb.WriteString("- " + subject + "\n")
+		}
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("Changed files:\n")
+	for i, file := range m.files {
+		switch {
+		case m.strategy == strategySinglePass && m.fileStatus[i] != 3:
+			sb.WriteString(file.Diff)
+			sb.WriteString("\n")
+		case m.summaries[i] != "":
+			sb.WriteString(fmt.Sprintf("- %s: %s\n", file.Path, strings.TrimSpace(m.summaries[i])))
+		}
+	}
+	if len(m.ignored) > 0 {
+		sb.WriteString(fmt.Sprintf("- also changed: %s\n", strings.Join(m.ignored, ", ")))
+	}
+
+	sb.WriteString("\nOutput:")
+	return sb.String()
+}
+
+// commitGroup holds commit subjects sharing the same type.
+type commitGroup struct {
+	name     string
+	subjects []string
+}
+
+// groupCommitsByType groups commit subjects by their conventional commit type,
+// in order of first appearance. Subjects without a type go to "other".
+func groupCommitsByType(commits []string) []commitGroup {
+	var groups []commitGroup
+	index := make(map[string]int)
+	for _, c := range commits {
+		name := "other"
+		if headerLine.MatchString(c) {
+			msgType, _, _ := parseHeader(c)
+			if t := normalizeType(msgType); isCommitType(t) {
+				name = t
+			}
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, commitGroup{name: name})
+		}
+		groups[i].subjects = append(groups[i].subjects, c)
+	}
+	return groups
+}
+
+// parsePRDescription splits the model output into title and Markdown body,
+// dropping a code fence around the whole answer and "Title:" or heading
+// markers around the title. Code blocks inside the body are kept.
+func parsePRDescription(raw string) PRDescription {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(strings.TrimSpace(raw), "\r\n", "\n"), "\n") {
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+	if n := len(lines); n >= 2 && strings.HasPrefix(lines[0], "```") && strings.TrimSpace(lines[n-1]) == "```" {
+		lines = lines[1 : n-1]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) == 0 {
+		return PRDescription{}
+	}
+
+	title := strings.TrimSpace(lines[0])
+	title = strings.TrimLeft(title, "# ")
+	for _, prefix := range []string{"Title:", "title:", "**Title:**"} {
+		title = strings.TrimSpace(strings.TrimPrefix(title, prefix))
+	}
+	title = stripDecoration(title)
+
+	body := strings.TrimSpace(strings.Join(lines[1:], "\n"))
+	return PRDescription{Title: title, Body: body}
+}
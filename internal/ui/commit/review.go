@@ -0,0 +1,293 @@
+package commit
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/mritd/gitflow-toolkit/v3/config"
+	"github.com/mritd/gitflow-toolkit/v3/internal/git"
+	"github.com/mritd/gitflow-toolkit/v3/internal/llm"
+	"github.com/mritd/gitflow-toolkit/v3/internal/ui/common"
+)
+
+// Review finding severities.
+const (
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+)
+
+// severityAliases maps other severity words models use to the supported severities.
+var severityAliases = map[string]string{
+	"critical": SeverityHigh,
+	"error":    SeverityHigh,
+	"major":    SeverityHigh,
+	"warning":  SeverityMedium,
+	"minor":    SeverityLow,
+	"info":     SeverityLow,
+}
+
+// findingLine matches "[high] message", "- HIGH: message" and similar finding lines.
+var findingLine = regexp.MustCompile(`(?i)^(?:[-*]\s*)?\[?\s*(high|medium|low|critical|error|major|warning|minor|info)\s*\]?\s*[:\-–]?\s*(.+)$`)
+
+// ReviewFinding is a single problem reported by the reviewer.
+type ReviewFinding struct {
+	Severity string
+	Message  string
+}
+
+// FileReview holds the findings for one file.
+type FileReview struct {
+	Path     string
+	Findings []ReviewFinding
+}
+
+// ReviewResult represents the result of an AI review.
+type ReviewResult struct {
+	Files     []FileReview // files with findings only
+	Reviewed  int          // number of files sent for review
+	Cancelled bool
+	Err       error
+}
+
+// RunReview reviews the staged changes file by file with the configured LLM.
+func RunReview(opts Options) ReviewResult {
+	input, err := prepareStagedInput(opts.NoCache)
+	if err != nil {
+		return ReviewResult{Err: err}
+	}
+	input.review = true
+
+	finalModel, err := tea.NewProgram(newAIModel(input, llm.NewClient())).Run()
+	if err != nil {
+		return ReviewResult{Err: err}
+	}
+
+	result := finalModel.(aiModel)
+	if result.cancelled {
+		return ReviewResult{Cancelled: true}
+	}
+	if result.err != nil {
+		return ReviewResult{Err: result.err}
+	}
+
+	return ReviewResult{
+		Files:    collectReviews(result),
+		Reviewed: len(result.files) - result.skippedCount,
+	}
+}
+
+// runReviewStep reviews the staged changes before AI generation if enabled by
+// --review or llm-review. Returns true if the user chose to abort the commit.
+func runReviewStep(opts Options) (bool, error) {
+	if !opts.Review && !config.GetBool(config.GitConfigLLMReview, false) {
+		return false, nil
+	}
+
+	result := RunReview(opts)
+	if result.Cancelled {
+		return true, nil
+	}
+	if result.Err != nil {
+		return false, result.Err
+	}
+	if len(result.Files) == 0 {
+		return false, nil
+	}
+
+	finalModel, err := tea.NewProgram(newReviewConfirmModel(result.Files)).Run()
+	if err != nil {
+		return false, err
+	}
+	return !finalModel.(reviewConfirmModel).proceed, nil
+}
+
+// buildReviewPrompt creates a prompt for reviewing a single file's changes.
+func buildReviewPrompt(file git.FileDiff) string {
+	return fmt.Sprintf(`Review the changes in this git diff.
+
+File: %s
+Diff:
+%s
+
+Findings:`, file.Path, file.Diff)
+}
+
+// collectReviews parses the review output of every analyzed file,
+// keeping only files with findings.
+func collectReviews(m aiModel) []FileReview {
+	var reviews []FileReview
+	for i, file := range m.files {
+		if m.fileStatus[i] != 2 { // not reviewed
+			continue
+		}
+		if findings := parseFindings(m.summaries[i]); len(findings) > 0 {
+			reviews = append(reviews, FileReview{Path: file.Path, Findings: findings})
+		}
+	}
+	return reviews
+}
+
+// parseFindings extracts findings from the reviewer output. Output that does not
+// follow the requested format is kept as a single low severity finding.
+func parseFindings(raw string) []ReviewFinding {
+	var findings []ReviewFinding
+	var other []string
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || isNoFindings(line) || strings.HasPrefix(line, "```") {
+			continue
+		}
+		match := findingLine.FindStringSubmatch(line)
+		if match == nil {
+			other = append(other, line)
+			continue
+		}
+		severity := strings.ToLower(match[1])
+		if alias, ok := severityAliases[severity]; ok {
+			severity = alias
+		}
+		findings = append(findings, ReviewFinding{Severity: severity, Message: strings.TrimSpace(match[2])})
+	}
+
+	if len(findings) == 0 && len(other) > 0 {
+		findings = append(findings, ReviewFinding{Severity: SeverityLow, Message: strings.Join(other, " ")})
+	}
+	return findings
+}
+
+// isNoFindings reports whether a line states that there are no problems.
+func isNoFindings(line string) bool {
+	l := strings.ToLower(strings.Trim(line, ".*` "))
+	return l == "none" || l == "no issues" || l == "no problems" || l == "no issues found" || l == "no problems found"
+}
+
+// FormatReview renders findings grouped by file, colored by severity.
+func FormatReview(files []FileReview) string {
+	fileStyle := lipgloss.NewStyle().Foreground(common.ColorPrimary).Bold(true)
+
+	var sb strings.Builder
+	for i, file := range files {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fileStyle.Render(file.Path))
+		sb.WriteString("\n")
+		for _, f := range file.Findings {
+			sb.WriteString("  " + severityStyle(f.Severity).Render(fmt.Sprintf("[%s]", f.Severity)) + " " + f.Message + "\n")
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// severityStyle returns the style for a severity label.
+func severityStyle(severity string) lipgloss.Style {
+	switch severity {
+	case SeverityHigh:
+		return lipgloss.NewStyle().Foreground(common.ColorError).Bold(true)
+	case SeverityMedium:
+		return lipgloss.NewStyle().Foreground(common.ColorWarning)
+	default:
+		return lipgloss.NewStyle().Foreground(common.ColorMuted)
+	}
+}
+
+// reviewConfirmModel shows review findings and asks whether to continue with the commit.
+type reviewConfirmModel struct {
+	files    []FileReview
+	selected int // 0=Continue, 1=Abort
+	proceed  bool
+	done     bool
+}
+
+func newReviewConfirmModel(files []FileReview) reviewConfirmModel {
+	return reviewConfirmModel{files: files}
+}
+
+func (m reviewConfirmModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m reviewConfirmModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "ctrl+c", "esc", "q", "a":
+			m.done = true
+			return m, tea.Quit
+		case "c", "y":
+			m.proceed = true
+			m.done = true
+			return m, tea.Quit
+		case "enter":
+			m.proceed = m.selected == 0
+			m.done = true
+			return m, tea.Quit
+		case "left", "h":
+			m.selected = 0
+		case "right", "l":
+			m.selected = 1
+		case "tab":
+			m.selected = (m.selected + 1) % 2
+		}
+	}
+	return m, nil
+}
+
+func (m reviewConfirmModel) View() string {
+	if m.done {
+		return ""
+	}
+
+	titleLayout := lipgloss.NewStyle().Padding(1, 0, 1, 2)
+	titleStyle := lipgloss.NewStyle().
+		Foreground(common.ColorTitleFg).
+		Background(common.ColorTitleBg).
+		Bold(true).
+		Padding(0, 1)
+
+	contentLayout := lipgloss.NewStyle().PaddingLeft(2)
+	contentStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(common.ColorWarning).
+		PaddingLeft(1)
+
+	activeStyle := lipgloss.NewStyle().
+		Foreground(common.ColorTitleFg).
+		Background(common.ColorSuccess).
+		Bold(true).
+		Padding(0, 2)
+	abortActiveStyle := lipgloss.NewStyle().
+		Foreground(common.ColorTitleFg).
+		Background(common.ColorError).
+		Bold(true).
+		Padding(0, 2)
+	inactiveStyle := lipgloss.NewStyle().
+		Foreground(common.ColorMuted).
+		Background(common.ColorButton).
+		Padding(0, 2)
+
+	continueBtn := inactiveStyle.Render("  Continue  ")
+	abortBtn := inactiveStyle.Render("  Abort  ")
+	if m.selected == 0 {
+		continueBtn = activeStyle.Render("  Continue  ")
+	} else {
+		abortBtn = abortActiveStyle.Render("  Abort  ")
+	}
+
+	buttonLayout := lipgloss.NewStyle().PaddingLeft(2).PaddingTop(1)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(common.ColorMuted).
+		PaddingLeft(2).
+		PaddingTop(1)
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		titleLayout.Render(titleStyle.Render("Review Findings")),
+		contentLayout.Render(contentStyle.Render(FormatReview(m.files))),
+		buttonLayout.Render(continueBtn+"  "+abortBtn),
+		helpStyle.Render("c continue • a/esc abort commit • ←/→ select • enter confirm"),
+	) + "\n"
+}
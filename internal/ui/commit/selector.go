@@ -17,47 +17,50 @@ import (
 // aiGenerateChoice is the special choice value for AI generation.
 const aiGenerateChoice = "__ai_generate__"
 
-// Styles for the selector
-var (
-	selectorTitleStyle = lipgloss.NewStyle().
-				Foreground(common.ColorTitleFg).
-				Background(common.ColorTitleBg).
-				Bold(true).
-				Padding(0, 1)
-
-	selectorNormalStyle = lipgloss.NewStyle().
-				Foreground(common.ColorText).
-				Padding(0, 0, 0, 2)
-
-	selectorSelectedStyle = lipgloss.NewStyle().
-				Border(lipgloss.NormalBorder(), false, false, false, true).
-				BorderForeground(common.ColorBorder).
-				Foreground(common.ColorPrimary).
-				Bold(true).
-				Padding(0, 0, 0, 1)
-
-	selectorInactiveSelectedStyle = lipgloss.NewStyle().
-					Foreground(common.ColorMuted).
-					Padding(0, 0, 0, 2)
-
-	selectorHelpStyle = lipgloss.NewStyle().
-				Foreground(common.ColorMuted)
-
-	selectorButtonLayout = lipgloss.NewStyle().
-				PaddingLeft(2).
-				PaddingTop(1)
-
-	selectorAIButtonStyle = lipgloss.NewStyle().
-				Foreground(common.ColorMuted).
-				Background(lipgloss.AdaptiveColor{Light: "#E0E0E0", Dark: "#3a3a3a"}).
-				Padding(0, 2)
-
-	selectorAIButtonActiveStyle = lipgloss.NewStyle().
-					Foreground(common.ColorTitleFg).
-					Background(common.ColorPrimary).
-					Bold(true).
-					Padding(0, 2)
-)
+// selectorStyles holds the selector styles, built from the current theme.
+type selectorStyles struct {
+	title            lipgloss.Style
+	normal           lipgloss.Style
+	selected         lipgloss.Style
+	inactiveSelected lipgloss.Style
+	buttonLayout     lipgloss.Style
+	aiButton         lipgloss.Style
+	aiButtonActive   lipgloss.Style
+}
+
+func newSelectorStyles() selectorStyles {
+	return selectorStyles{
+		title: lipgloss.NewStyle().
+			Foreground(common.ColorTitleFg).
+			Background(common.ColorTitleBg).
+			Bold(true).
+			Padding(0, 1),
+		normal: lipgloss.NewStyle().
+			Foreground(common.ColorText).
+			Padding(0, 0, 0, 2),
+		selected: lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			BorderForeground(common.ColorBorder).
+			Foreground(common.ColorPrimary).
+			Bold(true).
+			Padding(0, 0, 0, 1),
+		inactiveSelected: lipgloss.NewStyle().
+			Foreground(common.ColorMuted).
+			Padding(0, 0, 0, 2),
+		buttonLayout: lipgloss.NewStyle().
+			PaddingLeft(2).
+			PaddingTop(1),
+		aiButton: lipgloss.NewStyle().
+			Foreground(common.ColorMuted).
+			Background(common.ColorButton).
+			Padding(0, 2),
+		aiButtonActive: lipgloss.NewStyle().
+			Foreground(common.ColorTitleFg).
+			Background(common.ColorPrimary).
+			Bold(true).
+			Padding(0, 2),
+	}
+}
 
 // selectorItem represents a commit type item in the list.
 type selectorItem struct {
@@ -70,6 +73,7 @@ func (i selectorItem) FilterValue() string { return i.description }
 // selectorDelegate handles rendering of list items.
 type selectorDelegate struct {
 	inactive *bool // pointer to track if list is inactive (AI button selected)
+	styles   selectorStyles
 }
 
 func (d selectorDelegate) Height() int                             { return 1 }
@@ -90,22 +94,22 @@ func (d selectorDelegate) Render(w io.Writer, m list.Model, index int, listItem
 	if index == m.Index() {
 		if isInactive {
 			// Selected but inactive (AI button is focused)
-			_, _ = fmt.Fprint(w, selectorInactiveSelectedStyle.Render(str))
+			_, _ = fmt.Fprint(w, d.styles.inactiveSelected.Render(str))
 		} else {
-			_, _ = fmt.Fprint(w, selectorSelectedStyle.Render(str))
+			_, _ = fmt.Fprint(w, d.styles.selected.Render(str))
 		}
 	} else {
-		_, _ = fmt.Fprint(w, selectorNormalStyle.Render(str))
+		_, _ = fmt.Fprint(w, d.styles.normal.Render(str))
 	}
 }
 
-// selectorModel is the bubbletea model for commit type selection.
+// selectorModel is the commit type step of the commit composer.
+// The composer handles quitting and selecting.
 type selectorModel struct {
 	list       list.Model
 	delegate   *selectorDelegate
-	choice     string
+	styles     selectorStyles
 	aiSelected bool // true when AI option is focused
-	quitting   bool
 	width      int
 }
 
@@ -131,7 +135,8 @@ func newSelectorModel(initialType string) selectorModel {
 
 	// Create delegate with pointer to track inactive state
 	aiSelected := false
-	delegate := &selectorDelegate{inactive: &aiSelected}
+	styles := newSelectorStyles()
+	delegate := &selectorDelegate{inactive: &aiSelected, styles: styles}
 
 	// Create list with reasonable defaults
 	l := list.New(items, delegate, 40, 12)
@@ -139,7 +144,7 @@ func newSelectorModel(initialType string) selectorModel {
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(false)
 	l.SetShowHelp(false) // Disable built-in help, we render our own
-	l.Styles.Title = selectorTitleStyle
+	l.Styles.Title = styles.title
 	l.Styles.PaginationStyle = lipgloss.NewStyle().PaddingLeft(2)
 
 	// Set initial selection based on branch type
@@ -147,7 +152,7 @@ func newSelectorModel(initialType string) selectorModel {
 		l.Select(findTypeIndex(initialType))
 	}
 
-	return selectorModel{list: l, delegate: delegate, aiSelected: false}
+	return selectorModel{list: l, delegate: delegate, styles: styles, aiSelected: false}
 }
 
 func (m selectorModel) Init() tea.Cmd {
@@ -172,18 +177,6 @@ func (m selectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "ctrl+c", "q", "esc":
-			m.quitting = true
-			return m, tea.Quit
-
-		case "enter":
-			if m.aiSelected {
-				m.choice = aiGenerateChoice
-			} else if item, ok := m.list.SelectedItem().(selectorItem); ok {
-				m.choice = item.commitType
-			}
-			return m, tea.Quit
-
 		case "tab":
 			// Toggle between list and AI button
 			m.aiSelected = !m.aiSelected
@@ -208,11 +201,6 @@ func (m selectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				*m.delegate.inactive = false
 				return m, nil
 			}
-
-		case "a":
-			// Quick select AI option
-			m.choice = aiGenerateChoice
-			return m, tea.Quit
 		}
 	}
 
@@ -226,11 +214,19 @@ func (m selectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m selectorModel) View() string {
-	if m.choice != "" || m.quitting {
-		return ""
+// selected returns the highlighted commit type, or aiGenerateChoice when the
+// AI button is focused.
+func (m selectorModel) selected() string {
+	if m.aiSelected {
+		return aiGenerateChoice
 	}
+	if item, ok := m.list.SelectedItem().(selectorItem); ok {
+		return item.commitType
+	}
+	return ""
+}
 
+func (m selectorModel) View() string {
 	var sb strings.Builder
 	sb.WriteString("\n")
 	sb.WriteString(m.list.View())
@@ -240,11 +236,11 @@ func (m selectorModel) View() string {
 	aiText := "Auto Generate"
 	var button string
 	if m.aiSelected {
-		button = selectorAIButtonActiveStyle.Render(aiText)
+		button = m.styles.aiButtonActive.Render(aiText)
 	} else {
-		button = selectorAIButtonStyle.Render(aiText)
+		button = m.styles.aiButton.Render(aiText)
 	}
-	sb.WriteString(selectorButtonLayout.Render(button))
+	sb.WriteString(m.styles.buttonLayout.Render(button))
 	sb.WriteString("\n")
 
 	// Help text
@@ -252,30 +248,8 @@ func (m selectorModel) View() string {
 		Foreground(common.ColorMuted).
 		PaddingLeft(2).
 		PaddingTop(1)
-	sb.WriteString(helpStyle.Render("↑/↓ navigate • tab switch • enter select • a auto generate"))
+	sb.WriteString(helpStyle.Render("↑/↓ navigate • tab switch • enter select • a auto generate • esc quit"))
 	sb.WriteString("\n")
 
 	return sb.String()
 }
-
-// runSelector shows a selector for commit type using bubbles/list.
-// initialType is the commit type to pre-select (can be empty).
-func runSelector(initialType string) (string, error) {
-	m := newSelectorModel(initialType)
-	p := tea.NewProgram(m)
-
-	finalModel, err := p.Run()
-	if err != nil {
-		return "", fmt.Errorf("failed to run selector: %w", err)
-	}
-
-	result := finalModel.(selectorModel)
-	if result.quitting {
-		return "", errUserAborted
-	}
-
-	return result.choice, nil
-}
-
-// errUserAborted is returned when the user cancels the selection.
-var errUserAborted = fmt.Errorf("user aborted")
@@ -0,0 +1,530 @@
+package commit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/mritd/gitflow-toolkit/v3/internal/git"
+	"github.com/mritd/gitflow-toolkit/v3/internal/ui/common"
+)
+
+// maxUntrackedPreviewLines limits the preview of untracked files.
+const maxUntrackedPreviewLines = 500
+
+// errNothingStaged is shown when continuing without any staged change.
+var errNothingStaged = errors.New("Stage at least one file to continue")
+
+// StagingResult is the outcome of the staging step.
+type StagingResult struct {
+	Cancelled bool
+	Err       error
+}
+
+// NeedsStaging reports whether the staging step should run, which is when
+// nothing is staged yet or tracked files have unstaged changes. Untracked
+// files alone do not open it once something is staged.
+func NeedsStaging(files []git.FileStatus) bool {
+	staged := false
+	for _, f := range files {
+		if !f.Untracked && f.WorkTree != ' ' {
+			return true
+		}
+		staged = staged || f.Staged()
+	}
+	return len(files) > 0 && !staged
+}
+
+// RunStaging lets the user stage files or single hunks before composing the commit.
+func RunStaging(files []git.FileStatus) StagingResult {
+	m := newStagingModel(files)
+	finalModel, err := tea.NewProgram(m).Run()
+	if err != nil {
+		return StagingResult{Err: err}
+	}
+
+	m = finalModel.(stagingModel)
+	return StagingResult{Cancelled: m.cancelled}
+}
+
+// stagingStyles holds the staging screen styles, built from the current theme.
+type stagingStyles struct {
+	titleLayout lipgloss.Style
+	title       lipgloss.Style
+	fileFocus   lipgloss.Style
+	fileNormal  lipgloss.Style
+	staged      lipgloss.Style
+	partial     lipgloss.Style
+	unstaged    lipgloss.Style
+	section     lipgloss.Style
+	previewBox  lipgloss.Style
+	err         lipgloss.Style
+	help        lipgloss.Style
+	diff        diffStyles
+}
+
+func newStagingStyles() stagingStyles {
+	return stagingStyles{
+		titleLayout: lipgloss.NewStyle().
+			Padding(1, 0, 1, 2),
+		title: lipgloss.NewStyle().
+			Foreground(common.ColorTitleFg).
+			Background(common.ColorTitleBg).
+			Bold(true).
+			Padding(0, 1),
+		fileFocus: lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			BorderForeground(common.ColorBorder).
+			Foreground(common.ColorPrimary).
+			Bold(true).
+			Padding(0, 0, 0, 1),
+		fileNormal: lipgloss.NewStyle().
+			Foreground(common.ColorText).
+			Padding(0, 0, 0, 2),
+		staged: lipgloss.NewStyle().
+			Foreground(common.ColorSuccess),
+		partial: lipgloss.NewStyle().
+			Foreground(common.ColorWarning),
+		unstaged: lipgloss.NewStyle().
+			Foreground(common.ColorMuted),
+		section: lipgloss.NewStyle().
+			Foreground(common.ColorPrimary).
+			Bold(true),
+		previewBox: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(common.ColorBorder).
+			Padding(0, 1),
+		err: lipgloss.NewStyle().
+			Foreground(common.ColorError).
+			PaddingLeft(2).
+			PaddingTop(1),
+		help: lipgloss.NewStyle().
+			Foreground(common.ColorMuted).
+			PaddingLeft(2).
+			PaddingTop(1),
+		diff: newDiffStyles(),
+	}
+}
+
+// stagingHunk is a hunk of the selected file in hunk mode.
+type stagingHunk struct {
+	header string // diff header the hunk applies to
+	hunk   string
+	staged bool
+}
+
+// stagingModel is the bubbletea model for the staging step.
+type stagingModel struct {
+	files      []git.FileStatus
+	cursor     int
+	hunkMode   bool
+	hunks      []stagingHunk
+	hunkCursor int
+	hunkStarts []int    // first preview line of each hunk
+	preview    []string // colored preview lines of the selected file
+	offset     int      // first visible preview line
+	styles     stagingStyles
+	err        error
+	width      int
+	height     int
+	done       bool
+	cancelled  bool
+}
+
+func newStagingModel(files []git.FileStatus) stagingModel {
+	m := stagingModel{
+		files:  files,
+		styles: newStagingStyles(),
+		width:  80,
+		height: 24,
+	}
+	m.loadPreview()
+	return m
+}
+
+func (m stagingModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m stagingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.loadPreview()
+		return m, nil
+
+	case tea.KeyMsg:
+		m.err = nil
+		switch msg.String() {
+		case "ctrl+c", "q":
+			m.cancelled = true
+			return m, tea.Quit
+
+		case "enter":
+			if !m.anyStaged() {
+				m.err = errNothingStaged
+				return m, nil
+			}
+			m.done = true
+			return m, tea.Quit
+
+		case "pgdown", "ctrl+d", "J":
+			m.scroll(m.previewHeight() / 2)
+			return m, nil
+
+		case "pgup", "ctrl+u", "K":
+			m.scroll(-m.previewHeight() / 2)
+			return m, nil
+		}
+
+		if m.hunkMode {
+			return m.updateHunks(msg)
+		}
+		return m.updateFiles(msg)
+	}
+	return m, nil
+}
+
+func (m stagingModel) updateFiles(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.cancelled = true
+		return m, tea.Quit
+
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+			m.loadPreview()
+		}
+
+	case "down", "j":
+		if m.cursor < len(m.files)-1 {
+			m.cursor++
+			m.loadPreview()
+		}
+
+	case " ":
+		if f, ok := m.selected(); ok {
+			if f.Unstaged() {
+				m.apply(git.StageFile(f.Path))
+			} else {
+				m.apply(git.UnstageFile(f.Path))
+			}
+		}
+
+	case "a":
+		m.apply(git.StageAll())
+
+	case "h", "right":
+		if f, ok := m.selected(); ok {
+			if f.Untracked {
+				m.err = errors.New("Untracked files can only be staged as a whole")
+				return m, nil
+			}
+			m.hunkMode = true
+			m.hunkCursor = 0
+			m.loadPreview()
+		}
+	}
+	return m, nil
+}
+
+func (m stagingModel) updateHunks(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "h", "left":
+		m.hunkMode = false
+		m.loadPreview()
+
+	case "up", "k":
+		if m.hunkCursor > 0 {
+			m.hunkCursor--
+			m.scrollToHunk()
+		}
+
+	case "down", "j":
+		if m.hunkCursor < len(m.hunks)-1 {
+			m.hunkCursor++
+			m.scrollToHunk()
+		}
+
+	case " ":
+		if m.hunkCursor < len(m.hunks) {
+			h := m.hunks[m.hunkCursor]
+			if h.staged {
+				m.apply(git.UnstageHunk(h.header, h.hunk))
+			} else {
+				m.apply(git.StageHunk(h.header, h.hunk))
+			}
+		}
+	}
+	return m, nil
+}
+
+// apply records the error of a git operation and reloads the file list.
+func (m *stagingModel) apply(err error) {
+	if err != nil {
+		m.err = err
+	}
+	m.reload()
+}
+
+// reload refreshes the file list, keeping the selected file if it is still changed.
+func (m *stagingModel) reload() {
+	var path string
+	if f, ok := m.selected(); ok {
+		path = f.Path
+	}
+
+	files, err := git.WorkingTreeStatus()
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.files = files
+	m.cursor = min(m.cursor, max(len(files)-1, 0))
+	for i, f := range files {
+		if f.Path == path {
+			m.cursor = i
+			break
+		}
+	}
+	m.loadPreview()
+}
+
+// selected returns the file under the cursor.
+func (m stagingModel) selected() (git.FileStatus, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.files) {
+		return git.FileStatus{}, false
+	}
+	return m.files[m.cursor], true
+}
+
+// anyStaged reports whether at least one file has staged changes.
+func (m stagingModel) anyStaged() bool {
+	for _, f := range m.files {
+		if f.Staged() {
+			return true
+		}
+	}
+	return false
+}
+
+// loadPreview loads the diff of the selected file, or its hunks in hunk mode.
+func (m *stagingModel) loadPreview() {
+	m.preview = nil
+	m.hunkStarts = nil
+	m.offset = 0
+
+	f, ok := m.selected()
+	if !ok {
+		m.hunkMode = false
+		return
+	}
+	width := m.previewWidth()
+
+	if f.Untracked {
+		m.hunkMode = false
+		// Status paths are relative to the repository root, not the working directory
+		path, err := git.RepoPath(f.Path)
+		if err != nil {
+			path = f.Path
+		}
+		content := untrackedPreview(path)
+		m.preview = append([]string{m.styles.section.Render("Untracked")}, m.styles.diff.render(content, width)...)
+		return
+	}
+
+	staged, _ := git.GetFileDiff(f.Path, true)
+	unstaged, _ := git.GetFileDiff(f.Path, false)
+
+	if !m.hunkMode {
+		if staged != "" {
+			m.preview = append(m.preview, m.styles.section.Render("Staged"))
+			m.preview = append(m.preview, m.styles.diff.render(staged, width)...)
+		}
+		if unstaged != "" {
+			if len(m.preview) > 0 {
+				m.preview = append(m.preview, "")
+			}
+			m.preview = append(m.preview, m.styles.section.Render("Not staged"))
+			m.preview = append(m.preview, m.styles.diff.render(unstaged, width)...)
+		}
+		return
+	}
+
+	m.hunks = append(collectHunks(unstaged, false), collectHunks(staged, true)...)
+	if len(m.hunks) == 0 {
+		m.hunkMode = false
+		m.loadPreview()
+		return
+	}
+	m.hunkCursor = min(m.hunkCursor, len(m.hunks)-1)
+	for i, h := range m.hunks {
+		m.hunkStarts = append(m.hunkStarts, len(m.preview))
+		m.preview = append(m.preview, m.hunkTitle(i, h))
+		m.preview = append(m.preview, m.styles.diff.render(h.hunk, width)...)
+		m.preview = append(m.preview, "")
+	}
+	m.scrollToHunk()
+}
+
+// hunkTitle renders the line above a hunk with its staged state.
+func (m stagingModel) hunkTitle(i int, h stagingHunk) string {
+	title := m.styles.unstaged.Render(fmt.Sprintf("○ Hunk %d/%d (not staged)", i+1, len(m.hunks)))
+	if h.staged {
+		title = m.styles.staged.Render(fmt.Sprintf("● Hunk %d/%d (staged)", i+1, len(m.hunks)))
+	}
+	if i == m.hunkCursor {
+		return m.styles.section.Render("❯ ") + title
+	}
+	return "  " + title
+}
+
+// collectHunks splits a file diff into hunks.
+func collectHunks(diff string, staged bool) []stagingHunk {
+	if diff == "" {
+		return nil
+	}
+	header, hunks := git.SplitDiffHunks(diff)
+	result := make([]stagingHunk, len(hunks))
+	for i, h := range hunks {
+		result[i] = stagingHunk{header: header, hunk: h, staged: staged}
+	}
+	return result
+}
+
+// untrackedPreview returns the content of an untracked file as an added diff.
+func untrackedPreview(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err.Error()
+	}
+	if bytes.IndexByte(content, 0) >= 0 {
+		return "Binary file"
+	}
+
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	if len(lines) > maxUntrackedPreviewLines {
+		lines = append(lines[:maxUntrackedPreviewLines], fmt.Sprintf("... %d more lines", len(lines)-maxUntrackedPreviewLines))
+	}
+	for i, line := range lines {
+		lines[i] = "+" + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// scroll moves the preview by delta lines.
+func (m *stagingModel) scroll(delta int) {
+	m.offset = max(0, min(m.offset+delta, len(m.preview)-m.previewHeight()))
+}
+
+// scrollToHunk scrolls the preview to the hunk under the cursor and refreshes the hunk titles.
+func (m *stagingModel) scrollToHunk() {
+	for i, start := range m.hunkStarts {
+		m.preview[start] = m.hunkTitle(i, m.hunks[i])
+	}
+	if m.hunkCursor < len(m.hunkStarts) {
+		m.offset = 0
+		m.scroll(m.hunkStarts[m.hunkCursor])
+	}
+}
+
+// listWidth returns the width of the file list column.
+func (m stagingModel) listWidth() int {
+	return max(24, min(m.width*2/5, 50))
+}
+
+// previewWidth returns the width of the diff text inside the preview box.
+func (m stagingModel) previewWidth() int {
+	// Border and padding take 4 columns
+	return max(20, m.width-m.listWidth()-5)
+}
+
+// previewHeight returns the number of visible preview lines.
+func (m stagingModel) previewHeight() int {
+	// Title 3 lines, box border 2 lines, error and help 4 lines
+	return max(3, m.height-9)
+}
+
+func (m stagingModel) View() string {
+	if m.done || m.cancelled {
+		return ""
+	}
+
+	title := m.styles.titleLayout.Render(m.styles.title.Render("Stage Changes"))
+
+	list := lipgloss.NewStyle().Width(m.listWidth()).Render(m.fileList())
+	body := lipgloss.JoinHorizontal(lipgloss.Top, list, " ", m.previewView())
+
+	var sb strings.Builder
+	sb.WriteString(title)
+	sb.WriteString("\n")
+	sb.WriteString(body)
+	if m.err != nil {
+		sb.WriteString("\n")
+		sb.WriteString(m.styles.err.Render(m.err.Error()))
+	}
+	sb.WriteString("\n")
+
+	help := "↑/↓ select file • space stage/unstage • a stage all • h hunks • J/K scroll • enter continue • q quit"
+	if m.hunkMode {
+		help = "↑/↓ select hunk • space stage/unstage hunk • J/K scroll • esc files • enter continue • q quit"
+	}
+	sb.WriteString(m.styles.help.Render(help))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// fileList renders the changed files with their staged state.
+func (m stagingModel) fileList() string {
+	if len(m.files) == 0 {
+		return m.styles.fileNormal.Render("No changes")
+	}
+
+	// Keep the selected file visible
+	height := m.previewHeight() + 2
+	start := max(0, min(m.cursor-height/2, len(m.files)-height))
+	end := min(len(m.files), start+height)
+
+	lines := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		f := m.files[i]
+		code := string([]byte{f.Index, f.WorkTree})
+		path := lipgloss.NewStyle().MaxWidth(m.listWidth() - 9).Render(f.Path)
+		line := m.stateMarker(f) + " " + code + " " + path
+		if i == m.cursor {
+			lines = append(lines, m.styles.fileFocus.Render(line))
+		} else {
+			lines = append(lines, m.styles.fileNormal.Render(line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// stateMarker renders whether a file is staged, partly staged or not staged.
+func (m stagingModel) stateMarker(f git.FileStatus) string {
+	switch {
+	case f.Staged() && f.Unstaged():
+		return m.styles.partial.Render("◐")
+	case f.Staged():
+		return m.styles.staged.Render("●")
+	}
+	return m.styles.unstaged.Render("○")
+}
+
+// previewView renders the visible part of the preview.
+func (m stagingModel) previewView() string {
+	height := m.previewHeight()
+	end := min(len(m.preview), m.offset+height)
+	lines := append([]string(nil), m.preview[m.offset:end]...)
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return m.styles.previewBox.Width(m.previewWidth() + 2).Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,169 @@
+package commit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mritd/gitflow-toolkit/v3/consts"
+	"github.com/mritd/gitflow-toolkit/v3/internal/llm"
+)
+
+// errStructuredUnsupported is returned when the provider or model cannot produce JSON output.
+var errStructuredUnsupported = errors.New("structured output unsupported")
+
+// errNotJSON is returned when the model response contains no JSON object.
+var errNotJSON = errors.New("response is not a JSON object")
+
+// structuredMessage is the JSON schema requested from the model for commit messages.
+type structuredMessage struct {
+	Type    string   `json:"type"`
+	Scope   string   `json:"scope"`
+	Subject string   `json:"subject"`
+	Body    []string `json:"body"`
+}
+
+// parseStructuredMessage extracts and decodes the JSON object from a model response.
+// Tolerates surrounding chatter and markdown code fences.
+func parseStructuredMessage(raw string) (structuredMessage, error) {
+	var msg structuredMessage
+
+	start := strings.Index(raw, "{")
+	end := strings.LastIndex(raw, "}")
+	if start < 0 || end <= start {
+		return msg, errNotJSON
+	}
+
+	if err := json.Unmarshal([]byte(raw[start:end+1]), &msg); err != nil {
+		return msg, fmt.Errorf("%w: %v", errNotJSON, err)
+	}
+
+	msg.Type = strings.ToLower(strings.TrimSpace(msg.Type))
+	msg.Scope = strings.TrimSpace(msg.Scope)
+	msg.Subject = strings.TrimSpace(msg.Subject)
+	return msg, nil
+}
+
+// validate checks the message against the allowed commit types and the same
+// scope and subject rules as the manual inputs.
+func (s structuredMessage) validate() error {
+	if !isCommitType(s.Type) {
+		return fmt.Errorf("type %q is not one of: %s", s.Type, strings.Join(commitTypeNames(), ", "))
+	}
+	if err := validateScope(s.Scope); err != nil {
+		return err
+	}
+	if err := validateSubject(s.Subject); err != nil {
+		return err
+	}
+	if strings.ContainsAny(s.Subject, "\r\n") {
+		return errors.New("Subject must be a single line")
+	}
+	if strings.HasSuffix(s.Subject, ".") || strings.HasSuffix(s.Subject, "。") {
+		return errors.New("Subject must not end with a period")
+	}
+	return nil
+}
+
+// String formats the message as "type(scope): subject" followed by a bullet list body.
+func (s structuredMessage) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s(%s): %s", s.Type, s.Scope, s.Subject))
+
+	var bullets []string
+	for _, line := range s.Body {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "- "))
+		if line != "" {
+			bullets = append(bullets, "- "+line)
+		}
+	}
+	if len(bullets) > 0 {
+		sb.WriteString("\n\n")
+		sb.WriteString(strings.Join(bullets, "\n"))
+	}
+
+	return sb.String()
+}
+
+// isCommitType reports whether t is one of the supported commit types.
+func isCommitType(t string) bool {
+	for _, ct := range consts.CommitTypes {
+		if ct.Name == t {
+			return true
+		}
+	}
+	return false
+}
+
+// commitTypeNames returns the names of all supported commit types.
+func commitTypeNames() []string {
+	names := make([]string, len(consts.CommitTypes))
+	for i, ct := range consts.CommitTypes {
+		names[i] = ct.Name
+	}
+	return names
+}
+
+// exampleJSON converts a few-shot commit message example to the JSON object
+// requested in structured mode.
+func exampleJSON(example string) string {
+	lines := splitLines(example)
+	if len(lines) == 0 {
+		return example
+	}
+
+	msg := structuredMessage{Body: []string{}}
+	msg.Type, msg.Scope, msg.Subject = parseHeader(lines[0])
+	for _, line := range lines[1:] {
+		if line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "- ")); line != "" {
+			msg.Body = append(msg.Body, line)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(msg); err != nil {
+		return example
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// generateStructured requests a JSON commit message and validates it, re-prompting
+// with the validation error until it passes or the repair attempts are exhausted.
+// Returns errStructuredUnsupported if the provider rejects JSON mode or the model
+// does not return JSON, so the caller can fall back to free-text generation.
+func (m aiModel) generateStructured(prompt, systemPrompt string, temperature float64) (string, error) {
+	opt := llm.GenerateOptions{
+		System:      systemPrompt,
+		Temperature: temperature,
+		JSON:        true,
+	}
+
+	current := prompt
+	var lastErr error
+	for attempt := 0; attempt <= consts.LLMStructuredRepairAttempts; attempt++ {
+		raw, err := m.client.Generate(m.ctx, m.client.GetModel(), current, opt)
+		if err != nil {
+			if llm.IsUnsupportedError(err) {
+				return "", errStructuredUnsupported
+			}
+			return "", err
+		}
+
+		msg, err := parseStructuredMessage(raw)
+		if errors.Is(err, errNotJSON) {
+			return "", errStructuredUnsupported
+		}
+		if err = msg.validate(); err == nil {
+			return msg.String(), nil
+		}
+
+		lastErr = err
+		current = fmt.Sprintf("%s\n\nYour previous answer was rejected.\nAnswer: %s\nError: %s\nReturn a corrected JSON object.", prompt, raw, err)
+	}
+
+	return "", fmt.Errorf("model returned an invalid commit message: %w", lastErr)
+}
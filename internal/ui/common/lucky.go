@@ -1,103 +1,126 @@
 package common
 
 import (
-	"crypto/rand"
-	"encoding/hex"
-	"os/exec"
+	"context"
+	"fmt"
+	"math"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/mritd/gitflow-toolkit/v3/consts"
 )
 
 // LuckyResult represents the result of a lucky commit operation.
 type LuckyResult struct {
 	Cancelled bool
+	TimedOut  bool // search stopped after the timeout, original commit kept
 	Err       error
 	Hash      string
 }
 
-// luckyModel is the bubbletea model for lucky commit spinner.
+// LuckyOptions configures the lucky commit progress display.
+type LuckyOptions struct {
+	Prefix   string
+	Search   func(ctx context.Context) error // finds the hash and rewrites HEAD, stops when ctx is cancelled
+	Attempts func() uint64                   // real attempt count, nil if the search does not report it
+	Timeout  time.Duration                   // stop searching after this long, 0 for no timeout
+	GetHash  func() (string, error)          // returns the commit hash after success
+	HashRate func(d time.Duration) float64   // measures hashes per second for the ETA
+	Expected func(prefix string) float64     // average attempts needed for the prefix
+}
+
+// luckyModel is the bubbletea model for lucky commit progress.
 type luckyModel struct {
-	prefix      string
-	cmd         *exec.Cmd
-	spinner     spinner.Model
-	progressPos int
-	fakeHash    string
-	done        bool
-	success     bool
-	cancelled   bool
-	err         error
-	hash        string
-	getHash     func() (string, error)
-}
-
-// luckyDoneMsg is sent when lucky_commit completes.
+	opts      LuckyOptions
+	ctx       context.Context
+	cancel    context.CancelFunc
+	spinner   spinner.Model
+	expected  float64 // average attempts needed
+	rate      float64 // measured hashes per second, 0 while calibrating
+	started   time.Time
+	elapsed   time.Duration
+	tried     float64 // attempts so far, reported by the search or estimated from the rate
+	done      bool
+	success   bool
+	cancelled bool
+	timedOut  bool
+	err       error
+	hash      string
+}
+
+// luckyCalibratedMsg is sent when the hash rate has been measured.
+type luckyCalibratedMsg struct {
+	rate float64
+}
+
+// luckyDoneMsg is sent when the search completes.
 type luckyDoneMsg struct {
 	err error
 }
 
-// luckyTickMsg is sent for animation updates.
-type luckyTickMsg struct{}
+// luckyTickMsg is sent for progress updates.
+type luckyTickMsg time.Time
 
 // newLuckyModel creates a new lucky commit model.
-func newLuckyModel(prefix string, cmd *exec.Cmd, getHash func() (string, error)) luckyModel {
+func newLuckyModel(opts LuckyOptions) luckyModel {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(ColorPrimary)
 
+	ctx, cancel := context.WithCancel(context.Background())
 	return luckyModel{
-		prefix:      prefix,
-		cmd:         cmd,
-		spinner:     s,
-		progressPos: 0,
-		fakeHash:    generateFakeHash(),
-		getHash:     getHash,
+		opts:     opts,
+		ctx:      ctx,
+		cancel:   cancel,
+		spinner:  s,
+		expected: opts.Expected(opts.Prefix),
 	}
 }
 
-// generateFakeHash generates a random 40-char hex hash.
-func generateFakeHash() string {
-	randomBytes := make([]byte, 20)
-	_, _ = rand.Read(randomBytes)
-	return hex.EncodeToString(randomBytes)
-}
-
 // Init initializes the model.
 func (m luckyModel) Init() tea.Cmd {
 	return tea.Batch(
 		m.spinner.Tick,
-		m.runLuckyCommit(),
-		m.tickAnimation(),
+		m.calibrate(),
 	)
 }
 
-func (m luckyModel) runLuckyCommit() tea.Cmd {
+// calibrate measures the local hash rate before the search starts,
+// so the measurement does not compete with the search for CPU.
+func (m luckyModel) calibrate() tea.Cmd {
+	return func() tea.Msg {
+		return luckyCalibratedMsg{rate: m.opts.HashRate(consts.LuckyCalibrationTime)}
+	}
+}
+
+func (m luckyModel) runSearch() tea.Cmd {
 	return func() tea.Msg {
-		err := m.cmd.Run()
-		return luckyDoneMsg{err: err}
+		return luckyDoneMsg{err: m.opts.Search(m.ctx)}
 	}
 }
 
-func (m luckyModel) tickAnimation() tea.Cmd {
+func (m luckyModel) tick() tea.Cmd {
 	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
-		return luckyTickMsg{}
+		return luckyTickMsg(t)
 	})
 }
 
+// stop cancels the search, keeping the original commit.
+func (m luckyModel) stop() {
+	m.cancel()
+}
+
 // Update handles messages.
 func (m luckyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if msg.String() == "ctrl+c" {
 			m.cancelled = true
-			// Send SIGTERM to the process
-			if m.cmd.Process != nil {
-				_ = m.cmd.Process.Signal(syscall.SIGTERM)
-			}
+			m.stop()
 			return m, tea.Quit
 		}
 
@@ -106,21 +129,33 @@ func (m luckyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
 
+	case luckyCalibratedMsg:
+		m.rate = msg.rate
+		m.started = time.Now()
+		return m, tea.Batch(m.runSearch(), m.tick())
+
 	case luckyTickMsg:
-		if !m.done {
-			m.progressPos = (m.progressPos + 1) % 20
-			m.fakeHash = generateFakeHash()
-			return m, m.tickAnimation()
+		if m.done {
+			return m, nil
+		}
+		m.elapsed = time.Time(msg).Sub(m.started)
+		m.updateRate()
+		if m.opts.Timeout > 0 && m.elapsed >= m.opts.Timeout {
+			m.timedOut = true
+			m.stop()
+			return m, tea.Quit
 		}
+		return m, m.tick()
 
 	case luckyDoneMsg:
 		m.done = true
+		m.cancel()
 		if msg.err != nil {
 			m.err = msg.err
 		} else {
 			m.success = true
 			// Get the actual hash
-			if hash, err := m.getHash(); err == nil {
+			if hash, err := m.opts.GetHash(); err == nil {
 				m.hash = hash
 			}
 		}
@@ -132,7 +167,7 @@ func (m luckyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View renders the model.
 func (m luckyModel) View() string {
-	if m.done || m.cancelled {
+	if m.done || m.cancelled || m.timedOut {
 		return ""
 	}
 
@@ -148,15 +183,33 @@ func (m luckyModel) View() string {
 	sb.WriteString(titleLayout.Render(titleStyle.Render("Lucky Commit")))
 	sb.WriteString("\n")
 
-	// Progress bar with status
 	contentLayout := lipgloss.NewStyle().PaddingLeft(2)
-	progressBar := m.renderProgressBar()
-	sb.WriteString(contentLayout.Render(progressBar + "  Searching: " + m.prefix + "..."))
+	mutedStyle := lipgloss.NewStyle().Foreground(ColorMuted)
+
+	if m.rate == 0 {
+		sb.WriteString(contentLayout.Render(m.spinner.View() + " Measuring hash rate..."))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	// Progress bar towards the average expected time, with status
+	sb.WriteString(contentLayout.Render(m.renderProgressBar() + "  Searching: " + m.opts.Prefix + "..."))
 	sb.WriteString("\n\n")
 
-	// Current hash animation
-	hashStyle := lipgloss.NewStyle().Foreground(ColorMuted)
-	sb.WriteString(contentLayout.Render("Current: " + hashStyle.Render(m.fakeHash)))
+	sb.WriteString(contentLayout.Render(mutedStyle.Render(fmt.Sprintf("Expected: ~%s attempts at ~%s/s, tried %s",
+		formatCount(m.expected), formatCount(m.rate), m.triedLabel()))))
+	sb.WriteString("\n")
+
+	status := "Elapsed: " + formatETA(m.elapsed)
+	if remaining := m.expectedTime() - m.elapsed; remaining > 0 {
+		status += " • ETA: ~" + formatETA(remaining)
+	} else {
+		status += " • taking longer than average"
+	}
+	if m.opts.Timeout > 0 {
+		status += " • timeout in " + formatETA(m.opts.Timeout-m.elapsed)
+	}
+	sb.WriteString(contentLayout.Render(mutedStyle.Render(status)))
 	sb.WriteString("\n")
 
 	// Help text
@@ -170,21 +223,55 @@ func (m luckyModel) View() string {
 	return sb.String()
 }
 
+// updateRate updates the attempt count, and the rate once the search has run long
+// enough to measure it from real attempts.
+func (m *luckyModel) updateRate() {
+	if m.opts.Attempts == nil {
+		m.tried = m.rate * m.elapsed.Seconds()
+		return
+	}
+	m.tried = float64(m.opts.Attempts())
+	if m.elapsed >= time.Second && m.tried > 0 {
+		m.rate = m.tried / m.elapsed.Seconds()
+	}
+}
+
+// triedLabel returns the attempt count, marked as an estimate if not reported by the search.
+func (m luckyModel) triedLabel() string {
+	if m.opts.Attempts == nil {
+		return "~" + formatCount(m.tried)
+	}
+	return formatCount(m.tried)
+}
+
+// expectedTime returns the average search time at the measured rate.
+func (m luckyModel) expectedTime() time.Duration {
+	if m.rate <= 0 {
+		return 0
+	}
+	seconds := m.expected / m.rate
+	if seconds > math.MaxInt64/float64(time.Second) {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// progress returns the elapsed fraction of the expected time, capped at 1.
+func (m luckyModel) progress() float64 {
+	expected := m.expectedTime()
+	if expected <= 0 {
+		return 1
+	}
+	return math.Min(1, float64(m.elapsed)/float64(expected))
+}
+
 func (m luckyModel) renderProgressBar() string {
 	width := 20
-	pulseWidth := 6
+	filled := int(m.progress() * float64(width))
 
 	var bar strings.Builder
 	for i := 0; i < width; i++ {
-		// Create pulse effect - check if position is within pulse window
-		inPulse := false
-		for j := 0; j < pulseWidth; j++ {
-			if (m.progressPos+j)%width == i {
-				inPulse = true
-				break
-			}
-		}
-		if inPulse {
+		if i < filled {
 			bar.WriteString(lipgloss.NewStyle().Foreground(ColorSuccess).Render("█"))
 		} else {
 			bar.WriteString(lipgloss.NewStyle().Foreground(ColorMuted).Render("░"))
@@ -194,10 +281,40 @@ func (m luckyModel) renderProgressBar() string {
 	return bar.String()
 }
 
-// RunLuckyCommit runs lucky_commit with an animated spinner.
+// formatCount formats a large number with a metric suffix, e.g. 268.4M.
+func formatCount(n float64) string {
+	units := []string{"", "K", "M", "G", "T", "P"}
+	i := 0
+	for n >= 1000 && i < len(units)-1 {
+		n /= 1000
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f", n)
+	}
+	return fmt.Sprintf("%.1f%s", n, units[i])
+}
+
+// formatETA formats a duration with a precision suited to its size.
+func formatETA(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return "<1s"
+	case d < time.Hour:
+		return d.Round(time.Second).String()
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%.1fh", d.Hours())
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%.0fd", d.Hours()/24)
+	default:
+		return fmt.Sprintf("%.0fy", d.Hours()/24/365)
+	}
+}
+
+// RunLuckyCommit runs the lucky hash search with a progress display.
 // Returns the result of the operation.
-func RunLuckyCommit(prefix string, cmd *exec.Cmd, getHash func() (string, error)) LuckyResult {
-	m := newLuckyModel(prefix, cmd, getHash)
+func RunLuckyCommit(opts LuckyOptions) LuckyResult {
+	m := newLuckyModel(opts)
 	p := tea.NewProgram(m)
 
 	finalModel, err := p.Run()
@@ -208,6 +325,7 @@ func RunLuckyCommit(prefix string, cmd *exec.Cmd, getHash func() (string, error)
 	result := finalModel.(luckyModel)
 	return LuckyResult{
 		Cancelled: result.cancelled,
+		TimedOut:  result.timedOut,
 		Err:       result.err,
 		Hash:      result.hash,
 	}
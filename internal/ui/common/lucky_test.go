@@ -1,30 +1,144 @@
 package common
 
 import (
+	"context"
 	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
-func TestGenerateFakeHash(t *testing.T) {
-	for i := 0; i < 10; i++ {
-		hash := generateFakeHash()
+func testLuckyModel(prefix string, timeout time.Duration) luckyModel {
+	return newLuckyModel(LuckyOptions{
+		Prefix:   prefix,
+		Search:   func(ctx context.Context) error { <-ctx.Done(); return ctx.Err() },
+		Timeout:  timeout,
+		GetHash:  func() (string, error) { return "abc123", nil },
+		HashRate: func(time.Duration) float64 { return 1000 },
+		Expected: func(prefix string) float64 { return 16 * 16 * 16 * 16 },
+	})
+}
 
-		// Check length is 40
-		if len(hash) != 40 {
-			t.Errorf("generateFakeHash() length = %d, want 40", len(hash))
+func TestFormatCount(t *testing.T) {
+	tests := []struct {
+		n    float64
+		want string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1500, "1.5K"},
+		{268435456, "268.4M"},
+		{2.8e14, "280.0T"},
+	}
+
+	for _, tt := range tests {
+		if got := formatCount(tt.n); got != tt.want {
+			t.Errorf("formatCount(%v) = %q, want %q", tt.n, got, tt.want)
 		}
+	}
+}
+
+func TestFormatETA(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{300 * time.Millisecond, "<1s"},
+		{90*time.Second + 400*time.Millisecond, "1m30s"},
+		{3 * time.Hour, "3.0h"},
+		{10 * 24 * time.Hour, "10d"},
+		{3 * 365 * 24 * time.Hour, "3y"},
+	}
 
-		// Check all chars are hex
-		for _, c := range hash {
-			if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f')) {
-				t.Errorf("generateFakeHash() contains non-hex char: %c", c)
-			}
+	for _, tt := range tests {
+		if got := formatETA(tt.d); got != tt.want {
+			t.Errorf("formatETA(%v) = %q, want %q", tt.d, got, tt.want)
 		}
 	}
+}
+
+func TestLuckyModelProgress(t *testing.T) {
+	m := testLuckyModel("abcd", 0)
+	if m.expected != 65536 {
+		t.Fatalf("expected = %v, want 65536", m.expected)
+	}
+
+	model, _ := m.Update(luckyCalibratedMsg{rate: 1000})
+	m = model.(luckyModel)
+	if got := m.expectedTime(); got != 65536*time.Millisecond {
+		t.Errorf("expectedTime() = %v, want %v", got, 65536*time.Millisecond)
+	}
+
+	model, _ = m.Update(luckyTickMsg(m.started.Add(32768 * time.Millisecond)))
+	m = model.(luckyModel)
+	if got := m.progress(); got != 0.5 {
+		t.Errorf("progress() = %v, want 0.5", got)
+	}
+
+	model, _ = m.Update(luckyTickMsg(m.started.Add(time.Hour)))
+	m = model.(luckyModel)
+	if got := m.progress(); got != 1 {
+		t.Errorf("progress() after expected time = %v, want 1", got)
+	}
+}
 
-	// Check randomness (two calls should produce different results)
-	hash1 := generateFakeHash()
-	hash2 := generateFakeHash()
-	if hash1 == hash2 {
-		t.Errorf("generateFakeHash() produced same hash twice: %s", hash1)
+func TestLuckyModelTimeout(t *testing.T) {
+	m := testLuckyModel("abcd", 10*time.Second)
+	model, _ := m.Update(luckyCalibratedMsg{rate: 1000})
+	m = model.(luckyModel)
+
+	model, _ = m.Update(luckyTickMsg(m.started.Add(5 * time.Second)))
+	if model.(luckyModel).timedOut {
+		t.Fatal("should not time out before the timeout")
+	}
+
+	model, cmd := m.Update(luckyTickMsg(m.started.Add(10 * time.Second)))
+	if !model.(luckyModel).timedOut {
+		t.Error("should time out after the timeout")
+	}
+	if cmd == nil {
+		t.Fatal("timeout should quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("timeout should return tea.Quit")
+	}
+}
+
+func TestLuckyModelAttempts(t *testing.T) {
+	var attempts uint64
+	m := testLuckyModel("abcd", 0)
+	m.opts.Attempts = func() uint64 { return attempts }
+
+	model, _ := m.Update(luckyCalibratedMsg{rate: 1000})
+	m = model.(luckyModel)
+
+	// Before a second has passed the calibrated rate is kept
+	attempts = 100
+	model, _ = m.Update(luckyTickMsg(m.started.Add(500 * time.Millisecond)))
+	m = model.(luckyModel)
+	if m.tried != 100 || m.rate != 1000 {
+		t.Errorf("tried = %v, rate = %v, want 100, 1000", m.tried, m.rate)
+	}
+
+	// Afterwards the rate comes from the reported attempts
+	attempts = 6000
+	model, _ = m.Update(luckyTickMsg(m.started.Add(2 * time.Second)))
+	m = model.(luckyModel)
+	if m.rate != 3000 {
+		t.Errorf("rate = %v, want 3000", m.rate)
+	}
+	if got := m.triedLabel(); got != "6.0K" {
+		t.Errorf("triedLabel() = %q, want %q", got, "6.0K")
+	}
+}
+
+func TestLuckyModelStopCancelsSearch(t *testing.T) {
+	m := testLuckyModel("abcd", 0)
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if !model.(luckyModel).cancelled {
+		t.Error("ctrl+c should cancel")
+	}
+	if m.ctx.Err() == nil {
+		t.Error("ctrl+c should cancel the search context")
 	}
 }
@@ -0,0 +1,157 @@
+package common
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// plainMode is set when line-oriented prompts are used instead of the TUI.
+var plainMode bool
+
+// SetPlainMode enables or disables the plain-text UI.
+func SetPlainMode(plain bool) {
+	plainMode = plain
+}
+
+// PlainMode reports whether the plain-text UI is used, for screen readers and
+// terminals that cannot run the TUI.
+func PlainMode() bool {
+	return plainMode
+}
+
+// ErrPromptClosed is returned when the input ends before a question is answered.
+var ErrPromptClosed = errors.New("input closed")
+
+// Choice is an option of a numbered menu.
+type Choice struct {
+	Name        string
+	Description string
+}
+
+// Prompter asks questions one line at a time.
+type Prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// NewPrompter creates a prompter reading answers from in and writing questions to out.
+func NewPrompter(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{in: bufio.NewReader(in), out: out}
+}
+
+// Println prints a line of text.
+func (p *Prompter) Println(s string) {
+	_, _ = fmt.Fprintln(p.out, s)
+}
+
+// readLine reads one line without the line ending.
+func (p *Prompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil && line == "" {
+		return "", ErrPromptClosed
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// Ask prints the prompt and returns the answer without surrounding whitespace.
+func (p *Prompter) Ask(prompt string) (string, error) {
+	_, _ = fmt.Fprint(p.out, prompt+": ")
+	line, err := p.readLine()
+	return strings.TrimSpace(line), err
+}
+
+// AskValid asks until check accepts the answer, printing the reason for each rejection.
+func (p *Prompter) AskValid(prompt string, check func(string) error) (string, error) {
+	for {
+		answer, err := p.Ask(prompt)
+		if err != nil {
+			return "", err
+		}
+		if err := check(answer); err != nil {
+			p.Println("Error: " + err.Error())
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// AskLines reads a multi-line answer ending with an empty line.
+func (p *Prompter) AskLines(prompt string) (string, error) {
+	p.Println(prompt + ", end with an empty line:")
+	var lines []string
+	for {
+		line, err := p.readLine()
+		if err != nil || strings.TrimSpace(line) == "" {
+			// End of input also ends the answer
+			return strings.Join(lines, "\n"), nil
+		}
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+}
+
+// Choose prints a numbered menu and returns the index of the chosen option.
+// The answer is the option number or name; an empty answer picks def unless it is negative.
+func (p *Prompter) Choose(prompt string, choices []Choice, def int) (int, error) {
+	p.Println(prompt + ":")
+	for i, c := range choices {
+		p.Println(fmt.Sprintf("  %d. %s - %s", i+1, c.Name, c.Description))
+	}
+
+	question := fmt.Sprintf("Enter a number (1-%d) or name", len(choices))
+	if def >= 0 {
+		question += fmt.Sprintf(" [%s]", choices[def].Name)
+	}
+	for {
+		answer, err := p.Ask(question)
+		if err != nil {
+			return 0, err
+		}
+		if answer == "" && def >= 0 {
+			return def, nil
+		}
+		if idx, ok := findChoice(choices, answer); ok {
+			return idx, nil
+		}
+		p.Println(fmt.Sprintf("Error: %q is not one of the options", answer))
+	}
+}
+
+// findChoice returns the index of the choice matching a number or name.
+func findChoice(choices []Choice, answer string) (int, bool) {
+	if n, err := strconv.Atoi(answer); err == nil {
+		return n - 1, n >= 1 && n <= len(choices)
+	}
+	for i, c := range choices {
+		if strings.EqualFold(c.Name, answer) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Confirm asks a yes/no question; an empty answer returns def.
+func (p *Prompter) Confirm(prompt string, def bool) (bool, error) {
+	hint := " [y/N]"
+	if def {
+		hint = " [Y/n]"
+	}
+	for {
+		answer, err := p.Ask(prompt + hint)
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		p.Println("Error: please answer y or n")
+	}
+}
@@ -0,0 +1,123 @@
+package common
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newTestPrompter(input string) (*Prompter, *strings.Builder) {
+	var out strings.Builder
+	return NewPrompter(strings.NewReader(input), &out), &out
+}
+
+func TestPrompterChoose(t *testing.T) {
+	choices := []Choice{{"feat", "Feature"}, {"fix", "Bug fix"}, {"docs", "Docs"}}
+	tests := []struct {
+		name    string
+		input   string
+		def     int
+		want    int
+		wantErr error
+	}{
+		{name: "number", input: "2\n", def: -1, want: 1},
+		{name: "name", input: "DOCS\n", def: -1, want: 2},
+		{name: "default", input: "\n", def: 1, want: 1},
+		{name: "retry invalid", input: "9\nwip\n\n1\n", def: -1, want: 0},
+		{name: "closed", input: "", def: -1, wantErr: ErrPromptClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := newTestPrompter(tt.input)
+			got, err := p.Choose("Type", choices, tt.def)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Choose() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("Choose() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrompterAskValid(t *testing.T) {
+	p, out := newTestPrompter("\n  api  \n")
+	check := func(s string) error {
+		if s == "" {
+			return errors.New("Scope cannot be empty")
+		}
+		return nil
+	}
+
+	got, err := p.AskValid("Scope", check)
+	if err != nil {
+		t.Fatalf("AskValid() error = %v", err)
+	}
+	if got != "api" {
+		t.Errorf("AskValid() = %q, want %q", got, "api")
+	}
+	if !strings.Contains(out.String(), "Error: Scope cannot be empty") {
+		t.Errorf("output should explain the rejection, got %q", out.String())
+	}
+}
+
+func TestPrompterAskLines(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"first\n- second  \n\nignored\n", "first\n- second"},
+		{"\n", ""},
+		{"last line without newline", "last line without newline"},
+	}
+
+	for _, tt := range tests {
+		p, _ := newTestPrompter(tt.input)
+		got, err := p.AskLines("Body")
+		if err != nil {
+			t.Fatalf("AskLines() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("AskLines(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestPrompterConfirm(t *testing.T) {
+	tests := []struct {
+		input string
+		def   bool
+		want  bool
+	}{
+		{"y\n", false, true},
+		{"No\n", true, false},
+		{"\n", true, true},
+		{"\n", false, false},
+		{"maybe\nyes\n", false, true},
+	}
+
+	for _, tt := range tests {
+		p, _ := newTestPrompter(tt.input)
+		got, err := p.Confirm("Commit?", tt.def)
+		if err != nil {
+			t.Fatalf("Confirm(%q) error = %v", tt.input, err)
+		}
+		if got != tt.want {
+			t.Errorf("Confirm(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestRenderResultPlain(t *testing.T) {
+	SetPlainMode(true)
+	defer SetPlainMode(false)
+
+	r := Warning("Commit created", "feat(api): add")
+	r.Note = "lucky commit skipped"
+	got := RenderResult(r)
+	want := SymbolWarning + " Commit created\nfeat(api): add\nNote: lucky commit skipped\n"
+	if got != want {
+		t.Errorf("RenderResult() = %q, want %q", got, want)
+	}
+}
@@ -44,21 +44,25 @@ func containsANSI(s string) bool {
 // The output automatically adjusts to terminal width (capped at MaxContentWidth),
 // and preserves URLs and file paths on single lines without wrapping.
 func RenderResult(r Result) string {
+	if plainMode {
+		return renderPlainResult(r)
+	}
+
 	// Select colors based on result type
-	var bgColor, fgColor lipgloss.AdaptiveColor
+	var bgColor, fgColor lipgloss.TerminalColor
 	var symbol string
 	switch r.Type {
 	case ResultSuccess:
 		bgColor = ColorSuccess
-		fgColor = lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#FFFFFF"}
+		fgColor = ColorTitleFg
 		symbol = SymbolSuccess
 	case ResultError:
 		bgColor = ColorError
-		fgColor = lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#FFFFFF"}
+		fgColor = ColorTitleFg
 		symbol = SymbolError
 	case ResultWarning:
 		bgColor = ColorWarning
-		fgColor = lipgloss.AdaptiveColor{Light: "#1A1A1A", Dark: "#1A1A1A"}
+		fgColor = ColorWarningFg
 		symbol = SymbolWarning
 	}
 
@@ -120,6 +124,27 @@ func RenderResult(r Result) string {
 	return sb.String()
 }
 
+// renderPlainResult renders a Result as plain lines without borders or padding.
+func renderPlainResult(r Result) string {
+	symbol := SymbolSuccess
+	switch r.Type {
+	case ResultError:
+		symbol = SymbolError
+	case ResultWarning:
+		symbol = SymbolWarning
+	}
+
+	var sb strings.Builder
+	sb.WriteString(symbol + " " + r.Title + "\n")
+	if r.Content != "" {
+		sb.WriteString(r.Content + "\n")
+	}
+	if r.Note != "" {
+		sb.WriteString("Note: " + r.Note + "\n")
+	}
+	return sb.String()
+}
+
 func formatContent(content string, maxWidth int) string {
 	if maxWidth <= 0 {
 		maxWidth = 76
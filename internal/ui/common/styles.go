@@ -8,38 +8,47 @@ import (
 	"golang.org/x/term"
 )
 
-// Adaptive colors for light and dark terminals.
+// Theme colors, set by ApplyTheme. They default to the default theme.
 var (
-	ColorPrimary = lipgloss.AdaptiveColor{Light: "#9A4AFF", Dark: "#EE6FF8"}
-	ColorBorder  = lipgloss.AdaptiveColor{Light: "#9F72FF", Dark: "#AD58B4"}
-	ColorTitleBg = lipgloss.AdaptiveColor{Light: "#16A34A", Dark: "#22C55E"} // Unified green
-	ColorTitleFg = lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#FFFFFF"}
-	ColorSuccess = lipgloss.AdaptiveColor{Light: "#16A34A", Dark: "#22C55E"} // Same as TitleBg
-	ColorWarning = lipgloss.AdaptiveColor{Light: "#D97706", Dark: "#FBBF24"}
-	ColorError   = lipgloss.AdaptiveColor{Light: "#DC2626", Dark: "#F87171"}
-	ColorText    = lipgloss.AdaptiveColor{Light: "#1A1A1A", Dark: "#DDDDDD"}
-	ColorMuted   = lipgloss.AdaptiveColor{Light: "#6F6C6C", Dark: "#7A7A7A"}
+	ColorPrimary   lipgloss.TerminalColor
+	ColorBorder    lipgloss.TerminalColor
+	ColorTitleBg   lipgloss.TerminalColor
+	ColorTitleFg   lipgloss.TerminalColor
+	ColorSuccess   lipgloss.TerminalColor
+	ColorWarning   lipgloss.TerminalColor
+	ColorWarningFg lipgloss.TerminalColor // text on warning backgrounds
+	ColorError     lipgloss.TerminalColor
+	ColorText      lipgloss.TerminalColor
+	ColorMuted     lipgloss.TerminalColor
+	ColorButton    lipgloss.TerminalColor // inactive button background
+	ColorAccent    lipgloss.TerminalColor // commit inputs title background
+	ColorAlert     lipgloss.TerminalColor // validation error background
+	ColorEmphasis  lipgloss.TerminalColor // text typed into inputs
 
 	// Commit message colors
-	ColorCommitType    = lipgloss.AdaptiveColor{Light: "#CA8A04", Dark: "#FACC15"} // Yellow for type
-	ColorCommitScope   = lipgloss.AdaptiveColor{Light: "#C026D3", Dark: "#E879F9"} // Magenta for scope
-	ColorCommitSubject = lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#FFFFFF"} // White for subject
-	ColorCommitBody    = lipgloss.AdaptiveColor{Light: "#16A34A", Dark: "#4ADE80"} // Green for body
-	ColorCommitFooter  = lipgloss.AdaptiveColor{Light: "#2563EB", Dark: "#60A5FA"} // Blue for footer
-	ColorCommitSOB     = lipgloss.AdaptiveColor{Light: "#6B7280", Dark: "#9CA3AF"} // Gray for signed-off-by
+	ColorCommitType    lipgloss.TerminalColor
+	ColorCommitScope   lipgloss.TerminalColor
+	ColorCommitSubject lipgloss.TerminalColor
+	ColorCommitBody    lipgloss.TerminalColor
+	ColorCommitFooter  lipgloss.TerminalColor
+	ColorCommitSOB     lipgloss.TerminalColor
 )
 
-// Reusable lipgloss styles.
+// Reusable lipgloss styles, rebuilt by ApplyTheme.
 var (
-	StyleSuccess    = lipgloss.NewStyle().Foreground(ColorSuccess)
-	StyleWarning    = lipgloss.NewStyle().Foreground(ColorWarning)
-	StyleError      = lipgloss.NewStyle().Foreground(ColorError)
-	StyleMuted      = lipgloss.NewStyle().Foreground(ColorMuted)
-	StylePrimary    = lipgloss.NewStyle().Foreground(ColorPrimary)
-	StyleTitle      = lipgloss.NewStyle().Bold(true).Foreground(ColorPrimary).MarginBottom(1)
-	StyleCommitType = lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true)
+	StyleSuccess    lipgloss.Style
+	StyleWarning    lipgloss.Style
+	StyleError      lipgloss.Style
+	StyleMuted      lipgloss.Style
+	StylePrimary    lipgloss.Style
+	StyleTitle      lipgloss.Style
+	StyleCommitType lipgloss.Style
 )
 
+func init() {
+	ApplyTheme(DefaultTheme())
+}
+
 // Status indicator symbols.
 const (
 	SymbolSuccess = "✓"
@@ -47,6 +56,7 @@ const (
 	SymbolWarning = "⚠"
 	SymbolPending = "○"
 	SymbolRunning = "●"
+	SymbolSkipped = "⊘"
 )
 
 // MaxContentWidth defines the maximum display width for content.
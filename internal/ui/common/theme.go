@@ -0,0 +1,320 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/mritd/gitflow-toolkit/v3/config"
+	"github.com/mritd/gitflow-toolkit/v3/consts"
+)
+
+// Theme holds the colors used by every screen.
+type Theme struct {
+	Name          string
+	Primary       lipgloss.TerminalColor
+	Border        lipgloss.TerminalColor
+	TitleBg       lipgloss.TerminalColor
+	TitleFg       lipgloss.TerminalColor
+	Success       lipgloss.TerminalColor
+	Warning       lipgloss.TerminalColor
+	WarningFg     lipgloss.TerminalColor
+	Error         lipgloss.TerminalColor
+	Text          lipgloss.TerminalColor
+	Muted         lipgloss.TerminalColor
+	Button        lipgloss.TerminalColor
+	Accent        lipgloss.TerminalColor
+	Alert         lipgloss.TerminalColor
+	Emphasis      lipgloss.TerminalColor
+	CommitType    lipgloss.TerminalColor
+	CommitScope   lipgloss.TerminalColor
+	CommitSubject lipgloss.TerminalColor
+	CommitBody    lipgloss.TerminalColor
+	CommitFooter  lipgloss.TerminalColor
+	CommitSOB     lipgloss.TerminalColor
+}
+
+// adaptive returns a color for light and dark terminals.
+func adaptive(light, dark string) lipgloss.AdaptiveColor {
+	return lipgloss.AdaptiveColor{Light: light, Dark: dark}
+}
+
+// DefaultTheme returns the default theme.
+func DefaultTheme() Theme {
+	return Theme{
+		Name:          consts.ThemeDefault,
+		Primary:       adaptive("#9A4AFF", "#EE6FF8"),
+		Border:        adaptive("#9F72FF", "#AD58B4"),
+		TitleBg:       adaptive("#16A34A", "#22C55E"), // Unified green
+		TitleFg:       adaptive("#FFFFFF", "#FFFFFF"),
+		Success:       adaptive("#16A34A", "#22C55E"), // Same as TitleBg
+		Warning:       adaptive("#D97706", "#FBBF24"),
+		WarningFg:     adaptive("#1A1A1A", "#1A1A1A"),
+		Error:         adaptive("#DC2626", "#F87171"),
+		Text:          adaptive("#1A1A1A", "#DDDDDD"),
+		Muted:         adaptive("#6F6C6C", "#7A7A7A"),
+		Button:        adaptive("#DDDDDD", "#3A3A3A"),
+		Accent:        adaptive("#7653FF", "#7653FF"),
+		Alert:         adaptive("#FF6037", "#FF6037"),
+		Emphasis:      adaptive("#1A1A1A", "#FFFDF5"),
+		CommitType:    adaptive("#CA8A04", "#FACC15"), // Yellow for type
+		CommitScope:   adaptive("#C026D3", "#E879F9"), // Magenta for scope
+		CommitSubject: adaptive("#FFFFFF", "#FFFFFF"), // White for subject
+		CommitBody:    adaptive("#16A34A", "#4ADE80"), // Green for body
+		CommitFooter:  adaptive("#2563EB", "#60A5FA"), // Blue for footer
+		CommitSOB:     adaptive("#6B7280", "#9CA3AF"), // Gray for signed-off-by
+	}
+}
+
+// HighContrastTheme returns a theme with pure colors and black or white text.
+func HighContrastTheme() Theme {
+	return Theme{
+		Name:          consts.ThemeHighContrast,
+		Primary:       adaptive("#0000EE", "#FFFF00"),
+		Border:        adaptive("#0000EE", "#FFFF00"),
+		TitleBg:       adaptive("#000000", "#FFFFFF"),
+		TitleFg:       adaptive("#FFFFFF", "#000000"),
+		Success:       adaptive("#006400", "#00FF00"),
+		Warning:       adaptive("#8B4000", "#FFD700"),
+		WarningFg:     adaptive("#FFFFFF", "#000000"),
+		Error:         adaptive("#B00000", "#FF5555"),
+		Text:          adaptive("#000000", "#FFFFFF"),
+		Muted:         adaptive("#303030", "#D0D0D0"),
+		Button:        adaptive("#C0C0C0", "#303030"),
+		Accent:        adaptive("#0000EE", "#FFFF00"),
+		Alert:         adaptive("#B00000", "#FF5555"),
+		Emphasis:      adaptive("#000000", "#FFFFFF"),
+		CommitType:    adaptive("#8B4000", "#FFD700"),
+		CommitScope:   adaptive("#8B008B", "#FF77FF"),
+		CommitSubject: adaptive("#000000", "#FFFFFF"),
+		CommitBody:    adaptive("#006400", "#00FF00"),
+		CommitFooter:  adaptive("#0000EE", "#55AAFF"),
+		CommitSOB:     adaptive("#303030", "#D0D0D0"),
+	}
+}
+
+// MonochromeTheme returns a grayscale theme.
+func MonochromeTheme() Theme {
+	return Theme{
+		Name:          consts.ThemeMonochrome,
+		Primary:       adaptive("#000000", "#FFFFFF"),
+		Border:        adaptive("#555555", "#AAAAAA"),
+		TitleBg:       adaptive("#333333", "#DDDDDD"),
+		TitleFg:       adaptive("#FFFFFF", "#000000"),
+		Success:       adaptive("#222222", "#EEEEEE"),
+		Warning:       adaptive("#444444", "#CCCCCC"),
+		WarningFg:     adaptive("#FFFFFF", "#000000"),
+		Error:         adaptive("#000000", "#FFFFFF"),
+		Text:          adaptive("#1A1A1A", "#DDDDDD"),
+		Muted:         adaptive("#767676", "#8A8A8A"),
+		Button:        adaptive("#DDDDDD", "#3A3A3A"),
+		Accent:        adaptive("#333333", "#DDDDDD"),
+		Alert:         adaptive("#000000", "#FFFFFF"),
+		Emphasis:      adaptive("#000000", "#FFFFFF"),
+		CommitType:    adaptive("#000000", "#FFFFFF"),
+		CommitScope:   adaptive("#333333", "#CCCCCC"),
+		CommitSubject: adaptive("#000000", "#FFFFFF"),
+		CommitBody:    adaptive("#333333", "#CCCCCC"),
+		CommitFooter:  adaptive("#555555", "#AAAAAA"),
+		CommitSOB:     adaptive("#767676", "#8A8A8A"),
+	}
+}
+
+// SolarizedTheme returns a theme with the Solarized palette.
+func SolarizedTheme() Theme {
+	return Theme{
+		Name:          consts.ThemeSolarized,
+		Primary:       adaptive("#6C71C4", "#D33682"), // violet, magenta
+		Border:        adaptive("#6C71C4", "#6C71C4"),
+		TitleBg:       adaptive("#859900", "#859900"), // green
+		TitleFg:       adaptive("#FDF6E3", "#FDF6E3"), // base3
+		Success:       adaptive("#859900", "#859900"),
+		Warning:       adaptive("#B58900", "#B58900"), // yellow
+		WarningFg:     adaptive("#002B36", "#002B36"), // base03
+		Error:         adaptive("#DC322F", "#DC322F"), // red
+		Text:          adaptive("#657B83", "#839496"), // base00, base0
+		Muted:         adaptive("#93A1A1", "#586E75"), // base1, base01
+		Button:        adaptive("#EEE8D5", "#073642"), // base2, base02
+		Accent:        adaptive("#6C71C4", "#6C71C4"),
+		Alert:         adaptive("#CB4B16", "#CB4B16"), // orange
+		Emphasis:      adaptive("#073642", "#FDF6E3"),
+		CommitType:    adaptive("#B58900", "#B58900"),
+		CommitScope:   adaptive("#D33682", "#D33682"),
+		CommitSubject: adaptive("#073642", "#EEE8D5"),
+		CommitBody:    adaptive("#859900", "#859900"),
+		CommitFooter:  adaptive("#268BD2", "#268BD2"), // blue
+		CommitSOB:     adaptive("#93A1A1", "#586E75"),
+	}
+}
+
+// NoColorTheme returns a theme without any colors, used when NO_COLOR is set.
+func NoColorTheme() Theme {
+	t := Theme{Name: "none"}
+	for _, c := range t.colors() {
+		*c = lipgloss.NoColor{}
+	}
+	return t
+}
+
+// themes maps the built-in theme names to their constructors.
+var themes = map[string]func() Theme{
+	consts.ThemeDefault:      DefaultTheme,
+	consts.ThemeHighContrast: HighContrastTheme,
+	consts.ThemeMonochrome:   MonochromeTheme,
+	consts.ThemeSolarized:    SolarizedTheme,
+}
+
+// ThemeNames returns the names of the built-in themes.
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ThemeByName returns the built-in theme with the given name.
+func ThemeByName(name string) (Theme, error) {
+	newTheme, ok := themes[strings.ToLower(name)]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q, available: %s", name, strings.Join(ThemeNames(), ", "))
+	}
+	return newTheme(), nil
+}
+
+// colors maps the color names used in gitconfig overrides to the theme fields.
+func (t *Theme) colors() map[string]*lipgloss.TerminalColor {
+	return map[string]*lipgloss.TerminalColor{
+		"primary":        &t.Primary,
+		"border":         &t.Border,
+		"title-bg":       &t.TitleBg,
+		"title-fg":       &t.TitleFg,
+		"success":        &t.Success,
+		"warning":        &t.Warning,
+		"warning-fg":     &t.WarningFg,
+		"error":          &t.Error,
+		"text":           &t.Text,
+		"muted":          &t.Muted,
+		"button":         &t.Button,
+		"accent":         &t.Accent,
+		"alert":          &t.Alert,
+		"emphasis":       &t.Emphasis,
+		"commit-type":    &t.CommitType,
+		"commit-scope":   &t.CommitScope,
+		"commit-subject": &t.CommitSubject,
+		"commit-body":    &t.CommitBody,
+		"commit-footer":  &t.CommitFooter,
+		"commit-sob":     &t.CommitSOB,
+	}
+}
+
+// Override sets the named color from a gitconfig value.
+func (t *Theme) Override(name, value string) error {
+	field, ok := t.colors()[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("unknown theme color %q", name)
+	}
+	c, err := ParseColor(value)
+	if err != nil {
+		return fmt.Errorf("invalid theme color %s: %w", name, err)
+	}
+	*field = c
+	return nil
+}
+
+// ParseColor parses a color: a hex color (#RGB or #RRGGBB), an ANSI color number
+// (0-255), "none", or a "light,dark" pair of hex or ANSI colors.
+func ParseColor(value string) (lipgloss.TerminalColor, error) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "none") {
+		return lipgloss.NoColor{}, nil
+	}
+	if light, dark, ok := strings.Cut(value, ","); ok {
+		light, dark = strings.TrimSpace(light), strings.TrimSpace(dark)
+		if !validColor(light) || !validColor(dark) {
+			return nil, fmt.Errorf("%q is not a light,dark color pair", value)
+		}
+		return adaptive(light, dark), nil
+	}
+	if !validColor(value) {
+		return nil, fmt.Errorf("%q is not a hex or ANSI color", value)
+	}
+	return lipgloss.Color(value), nil
+}
+
+// validColor reports whether s is a hex color or an ANSI color number.
+func validColor(s string) bool {
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		if len(hex) != 3 && len(hex) != 6 {
+			return false
+		}
+		_, err := strconv.ParseUint(hex, 16, 32)
+		return err == nil
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// LoadTheme returns the theme configured with gitflow.theme and the gitflow.color-*
+// overrides. NO_COLOR disables all colors and takes precedence over the configuration.
+func LoadTheme() (Theme, error) {
+	if os.Getenv("NO_COLOR") != "" {
+		return NoColorTheme(), nil
+	}
+
+	t, err := ThemeByName(config.GetString(config.GitConfigTheme, consts.ThemeDefault))
+	if err != nil {
+		return DefaultTheme(), err
+	}
+
+	overrides := config.GetPrefixed(config.GitConfigColorPrefix)
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names) // report errors in a stable order
+	for _, name := range names {
+		if err := t.Override(name, overrides[name]); err != nil {
+			return DefaultTheme(), err
+		}
+	}
+	return t, nil
+}
+
+// ApplyTheme sets the theme colors and rebuilds the shared styles.
+// Screens build their styles when created, so it must be called before any UI runs.
+func ApplyTheme(t Theme) {
+	ColorPrimary = t.Primary
+	ColorBorder = t.Border
+	ColorTitleBg = t.TitleBg
+	ColorTitleFg = t.TitleFg
+	ColorSuccess = t.Success
+	ColorWarning = t.Warning
+	ColorWarningFg = t.WarningFg
+	ColorError = t.Error
+	ColorText = t.Text
+	ColorMuted = t.Muted
+	ColorButton = t.Button
+	ColorAccent = t.Accent
+	ColorAlert = t.Alert
+	ColorEmphasis = t.Emphasis
+	ColorCommitType = t.CommitType
+	ColorCommitScope = t.CommitScope
+	ColorCommitSubject = t.CommitSubject
+	ColorCommitBody = t.CommitBody
+	ColorCommitFooter = t.CommitFooter
+	ColorCommitSOB = t.CommitSOB
+
+	StyleSuccess = lipgloss.NewStyle().Foreground(ColorSuccess)
+	StyleWarning = lipgloss.NewStyle().Foreground(ColorWarning)
+	StyleError = lipgloss.NewStyle().Foreground(ColorError)
+	StyleMuted = lipgloss.NewStyle().Foreground(ColorMuted)
+	StylePrimary = lipgloss.NewStyle().Foreground(ColorPrimary)
+	StyleTitle = lipgloss.NewStyle().Bold(true).Foreground(ColorPrimary).MarginBottom(1)
+	StyleCommitType = lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true)
+}
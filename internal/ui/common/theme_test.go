@@ -0,0 +1,110 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestThemesComplete(t *testing.T) {
+	for _, name := range ThemeNames() {
+		t.Run(name, func(t *testing.T) {
+			theme, err := ThemeByName(name)
+			if err != nil {
+				t.Fatalf("ThemeByName(%q) error = %v", name, err)
+			}
+			if theme.Name != name {
+				t.Errorf("Name = %q, want %q", theme.Name, name)
+			}
+			for color, c := range theme.colors() {
+				if *c == nil {
+					t.Errorf("color %s is not set", color)
+				}
+			}
+		})
+	}
+}
+
+func TestThemeByNameUnknown(t *testing.T) {
+	if _, err := ThemeByName("neon"); err == nil {
+		t.Error("ThemeByName() should fail for an unknown theme")
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    lipgloss.TerminalColor
+		wantErr bool
+	}{
+		{value: "#FF0000", want: lipgloss.Color("#FF0000")},
+		{value: "#f00", want: lipgloss.Color("#f00")},
+		{value: "208", want: lipgloss.Color("208")},
+		{value: "none", want: lipgloss.NoColor{}},
+		{value: "#000000, #FFFFFF", want: lipgloss.AdaptiveColor{Light: "#000000", Dark: "#FFFFFF"}},
+		{value: "red", wantErr: true},
+		{value: "#12345", wantErr: true},
+		{value: "256", wantErr: true},
+		{value: "#000000,blue", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseColor(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseColor(%q) should fail", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseColor(%q) error = %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseColor(%q) = %#v, want %#v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThemeOverride(t *testing.T) {
+	theme := DefaultTheme()
+	if err := theme.Override("Primary", "#123456"); err != nil {
+		t.Fatalf("Override() error = %v", err)
+	}
+	if theme.Primary != lipgloss.Color("#123456") {
+		t.Errorf("Primary = %#v, want #123456", theme.Primary)
+	}
+	if err := theme.Override("background", "#123456"); err == nil {
+		t.Error("Override() should fail for an unknown color")
+	}
+	if err := theme.Override("button", "grey"); err == nil {
+		t.Error("Override() should fail for an invalid value")
+	}
+}
+
+func TestLoadThemeNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	theme, err := LoadTheme()
+	if err != nil {
+		t.Fatalf("LoadTheme() error = %v", err)
+	}
+	for color, c := range theme.colors() {
+		if _, ok := (*c).(lipgloss.NoColor); !ok {
+			t.Errorf("color %s = %#v, want NoColor", color, *c)
+		}
+	}
+}
+
+func TestApplyTheme(t *testing.T) {
+	defer ApplyTheme(DefaultTheme())
+
+	theme := SolarizedTheme()
+	ApplyTheme(theme)
+	if ColorButton != theme.Button || ColorPrimary != theme.Primary {
+		t.Error("ApplyTheme() should set the theme colors")
+	}
+	if StylePrimary.GetForeground() != theme.Primary {
+		t.Error("ApplyTheme() should rebuild the shared styles")
+	}
+}